@@ -0,0 +1,190 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CatalogEntry describes a detail code registered in a Catalog.
+type CatalogEntry struct {
+	// Code is the detail code, unique within the catalog.
+	Code string
+	// Domain is the default detail domain.
+	Domain string
+	// Description is the default detail description.
+	Description string
+	// Kind is the kind of errors the code is reported with.
+	Kind Kind
+	// HelpURL is the link to the code's documentation.
+	HelpURL string
+	// MetaKeys are the keys expected in the detail's Meta.
+	MetaKeys []string
+}
+
+// New creates an error of the entry's kind with a detail filled in from the
+// entry. The options are applied to the detail after the entry's defaults.
+func (e CatalogEntry) New(msg string, opts ...Option) error {
+	detail := NewDetail(append([]Option{e.option()}, opts...)...)
+	if e.Kind == "" {
+		return wrap(nil, msg, []Detail{detail})
+	}
+
+	return wrap(e.Kind, msg, []Detail{detail})
+}
+
+// clone returns a copy of the entry that shares no slices with the original one.
+func (e CatalogEntry) clone() CatalogEntry {
+	if e.MetaKeys != nil {
+		e.MetaKeys = append([]string(nil), e.MetaKeys...)
+	}
+
+	return e
+}
+
+func (e CatalogEntry) option() Option {
+	return func(d *Detail) {
+		WithCode(e.Code)(d)
+		WithDomain(e.Domain)(d)
+		WithDescription(e.Description)(d)
+		WithHelpURL(e.HelpURL)(d)
+	}
+}
+
+// Catalog is a registry of detail codes. The zero Catalog is empty
+// and ready to use. A Catalog must not be copied after first use.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries []CatalogEntry
+	index   map[string]int
+}
+
+// Register adds the entry to the catalog. Register is intended to be called
+// on a package initialization, it panics if the entry has no code or the code
+// is already registered.
+func (c *Catalog) Register(entry CatalogEntry) CatalogEntry {
+	if entry.Code == "" {
+		panic("errdetail: catalog entry has no code")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.index[entry.Code]; ok {
+		panic(fmt.Sprintf("errdetail: code %q is already registered", entry.Code))
+	}
+
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+
+	entry = entry.clone()
+	c.index[entry.Code] = len(c.entries)
+	c.entries = append(c.entries, entry)
+
+	return entry.clone()
+}
+
+// Lookup returns the entry registered with the code, if any.
+func (c *Catalog) Lookup(code string) (CatalogEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	i, ok := c.index[code]
+	if !ok {
+		return CatalogEntry{}, false
+	}
+
+	return c.entries[i].clone(), true
+}
+
+// Entries returns all registered entries in the order of registration.
+func (c *Catalog) Entries() []CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]CatalogEntry, len(c.entries))
+	for i := range c.entries {
+		entries[i] = c.entries[i].clone()
+	}
+
+	return entries
+}
+
+// FromCatalog is an option for Detail constructs that fills in the code,
+// the domain, the description and the help URL of the entry registered in
+// the catalog with the code, and marks the detail as not empty. Options
+// following FromCatalog override the defaults. If the code is not registered,
+// only the code is set.
+func (c *Catalog) FromCatalog(code string) Option {
+	return func(d *Detail) {
+		entry, ok := c.Lookup(code)
+		if !ok {
+			WithCode(code)(d)
+
+			return
+		}
+
+		entry.option()(d)
+	}
+}
+
+// Validate checks the detail against the catalog. It returns an error if
+// the detail's code is not registered or the detail's Meta misses any of
+// the expected keys.
+func (c *Catalog) Validate(detail Detail) error {
+	entry, ok := c.Lookup(detail.code)
+	if !ok {
+		return fmt.Errorf("errdetail: code %q is not registered", detail.code)
+	}
+
+	var missing []string
+
+	for _, key := range entry.MetaKeys {
+		if _, ok := detail.meta[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) != 0 {
+		sort.Strings(missing)
+
+		return fmt.Errorf("errdetail: detail %q misses meta keys: %s", detail.code, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// defaultCatalog is the catalog used by the package level functions.
+var defaultCatalog Catalog //nolint:gochecknoglobals // the registry is shared by the package
+
+// RegisterCode adds the entry to the default catalog, see Catalog.Register.
+func RegisterCode(entry CatalogEntry) CatalogEntry {
+	return defaultCatalog.Register(entry)
+}
+
+// FromCatalog is an option for Detail constructs that fills in the defaults
+// of the code registered in the default catalog, see Catalog.FromCatalog.
+func FromCatalog(code string) Option {
+	return defaultCatalog.FromCatalog(code)
+}
+
+// CatalogEntries returns all entries registered in the default catalog.
+func CatalogEntries() []CatalogEntry {
+	return defaultCatalog.Entries()
+}
+
+// LookupCode returns the entry registered in the default catalog with the code, if any.
+func LookupCode(code string) (CatalogEntry, bool) {
+	return defaultCatalog.Lookup(code)
+}
+
+// ValidateDetail checks the detail against the default catalog, see Catalog.Validate.
+func ValidateDetail(detail Detail) error {
+	return defaultCatalog.Validate(detail)
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+//nolint:gochecknoglobals // catalog entries are registered on a package initialization
+var emailTaken = RegisterCode(CatalogEntry{
+	Code:        "catalog_email_taken",
+	Domain:      "user.management",
+	Description: "email is already taken",
+	Kind:        ErrAlreadyExists,
+	HelpURL:     "https://example.com/errors#catalog_email_taken",
+	MetaKeys:    []string{"email"},
+})
+
+func TestCatalog_Register(t *testing.T) {
+	t.Parallel()
+
+	var catalog Catalog
+
+	first := catalog.Register(CatalogEntry{Code: "first", Kind: ErrInvalidArgument, MetaKeys: []string{"a"}})
+	catalog.Register(CatalogEntry{Code: "second"})
+
+	first.MetaKeys[0] = "b"
+
+	entry, ok := catalog.Lookup("first")
+	assert.True(t, ok)
+	assert.Equal(t, CatalogEntry{Code: "first", Kind: ErrInvalidArgument, MetaKeys: []string{"a"}}, entry)
+
+	_, ok = catalog.Lookup("third")
+	assert.False(t, ok)
+
+	assert.Equal(t, []CatalogEntry{
+		{Code: "first", Kind: ErrInvalidArgument, MetaKeys: []string{"a"}},
+		{Code: "second"},
+	}, catalog.Entries())
+
+	assert.PanicsWithValue(t, `errdetail: code "first" is already registered`, func() {
+		catalog.Register(CatalogEntry{Code: "first", Domain: "other"})
+	})
+	assert.PanicsWithValue(t, "errdetail: catalog entry has no code", func() {
+		catalog.Register(CatalogEntry{Domain: "other"})
+	})
+	assert.Len(t, catalog.Entries(), 2)
+}
+
+func TestFromCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts []Option
+		want Detail
+	}{
+		"defaults": {
+			opts: []Option{FromCatalog("catalog_email_taken")},
+			want: NewDetail(
+				WithCode("catalog_email_taken"),
+				WithDomain("user.management"),
+				WithDescription("email is already taken"),
+				WithHelpURL("https://example.com/errors#catalog_email_taken"),
+			),
+		},
+		"overrides": {
+			opts: []Option{
+				FromCatalog("catalog_email_taken"),
+				WithDescription("email is in use"),
+				WithField("user.email"),
+			},
+			want: NewDetail(
+				WithCode("catalog_email_taken"),
+				WithDomain("user.management"),
+				WithDescription("email is in use"),
+				WithHelpURL("https://example.com/errors#catalog_email_taken"),
+				WithField("user.email"),
+			),
+		},
+		"unknown_code": {
+			opts: []Option{FromCatalog("catalog_unknown")},
+			want: NewDetail(WithCode("catalog_unknown")),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, NewDetail(tt.opts...))
+		})
+	}
+}
+
+func TestCatalogEntry_New(t *testing.T) {
+	t.Parallel()
+
+	err := emailTaken.New("sign up", WithMeta(Meta{"email": "user@example.com"}))
+
+	assert.EqualError(t, err, "sign up: already exists")
+	assert.True(t, errors.Is(err, ErrAlreadyExists))
+	assert.Equal(t, []Detail{NewDetail(
+		FromCatalog("catalog_email_taken"),
+		WithMeta(Meta{"email": "user@example.com"}),
+	)}, ExtractDetails(err))
+
+	err = CatalogEntry{Code: "no_kind"}.New("dummy message")
+	assert.EqualError(t, err, "dummy message")
+	assert.Equal(t, Kind(""), KindOf(err))
+}
+
+func TestValidateDetail(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		detail Detail
+		err    string
+	}{
+		"valid": {
+			detail: NewDetail(FromCatalog("catalog_email_taken"), WithMeta(Meta{"email": "user@example.com"})),
+		},
+		"missing_meta": {
+			detail: NewDetail(FromCatalog("catalog_email_taken")),
+			err:    `errdetail: detail "catalog_email_taken" misses meta keys: email`,
+		},
+		"unknown_code": {
+			detail: NewDetail(WithCode("catalog_unknown")),
+			err:    `errdetail: code "catalog_unknown" is not registered`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateDetail(tt.detail)
+			if tt.err == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestCatalogEntries(t *testing.T) {
+	t.Parallel()
+
+	entry, ok := LookupCode("catalog_email_taken")
+	require.True(t, ok)
+	assert.Equal(t, emailTaken, entry)
+	assert.Contains(t, CatalogEntries(), emailTaken)
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package catalogdoc generates Markdown and HTML reference pages for
+// error catalogs, so support engineers and API clients can look codes up.
+package catalogdoc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+)
+
+// DefaultTitle is the default title of reference pages.
+const DefaultTitle = "Error codes"
+
+// Entry describes a detail code on a reference page.
+type Entry struct {
+	// Code is the detail code.
+	Code string
+	// Domain is the detail domain, entries are grouped by domains.
+	Domain string
+	// Description is the detail description.
+	Description string
+	// Kind is the kind of errors the code is reported with.
+	Kind errdetail.Kind
+	// BaseKind is the kind that determines the HTTP status and the gRPC code,
+	// for custom kinds that are not defined in the current process. Kind is
+	// used if BaseKind is empty.
+	BaseKind errdetail.Kind
+	// HelpURL is the link to the code's documentation.
+	HelpURL string
+	// Meta describes the keys expected in the detail's Meta.
+	Meta []MetaField
+}
+
+// MetaField describes a key of the detail's Meta.
+type MetaField struct {
+	Key         string
+	Type        string
+	Description string
+}
+
+// FromCatalog converts catalog entries into reference page entries.
+// Meta fields have neither type nor description, since catalogs hold
+// Meta keys only.
+func FromCatalog(entries []errdetail.CatalogEntry) []Entry {
+	converted := make([]Entry, len(entries))
+
+	for i, entry := range entries {
+		converted[i] = Entry{
+			Code:        entry.Code,
+			Domain:      entry.Domain,
+			Description: entry.Description,
+			Kind:        entry.Kind,
+			HelpURL:     entry.HelpURL,
+		}
+
+		for _, key := range entry.MetaKeys {
+			converted[i].Meta = append(converted[i].Meta, MetaField{Key: key})
+		}
+	}
+
+	return converted
+}
+
+// Anchor returns the anchor of the code on reference pages. Help URLs of
+// details can point at it, e.g. https://example.com/errors.html#email-taken.
+func Anchor(code string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(code) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+
+			continue
+		}
+
+		if b.Len() != 0 && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte('-')
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// Option is a function type for reference page settings' setters.
+type Option func(*settings)
+
+type settings struct {
+	title  string
+	mapper *httperr.Mapper
+}
+
+// WithTitle is an option for reference page writers that sets the page title.
+func WithTitle(title string) Option {
+	return func(s *settings) {
+		s.title = title
+	}
+}
+
+// WithMapper is an option for reference page writers that sets the Mapper
+// used to determine the HTTP statuses of the codes.
+func WithMapper(mapper *httperr.Mapper) Option {
+	return func(s *settings) {
+		s.mapper = mapper
+	}
+}
+
+func newSettings(opts []Option) settings {
+	s := settings{
+		title:  DefaultTitle,
+		mapper: httperr.NewMapper(),
+	}
+
+	for i := range opts {
+		opts[i](&s)
+	}
+
+	return s
+}
+
+// page is the template data of a reference page.
+type page struct {
+	Title   string
+	Domains []domain
+}
+
+type domain struct {
+	Name  string
+	Codes []code
+}
+
+type code struct {
+	Entry
+	Anchor string
+	Kind   string
+	HTTP   string
+	GRPC   string
+}
+
+// newPage groups the entries by domains in the order of their first appearance.
+func newPage(entries []Entry, s settings) page {
+	p := page{Title: s.title}
+	index := make(map[string]int)
+
+	for _, entry := range entries {
+		i, ok := index[entry.Domain]
+		if !ok {
+			i = len(p.Domains)
+			index[entry.Domain] = i
+			p.Domains = append(p.Domains, domain{Name: entry.Domain})
+		}
+
+		p.Domains[i].Codes = append(p.Domains[i].Codes, newCode(entry, s))
+	}
+
+	return p
+}
+
+func newCode(entry Entry, s settings) code {
+	base := entry.BaseKind
+	if base == "" {
+		base = entry.Kind
+	}
+
+	_, mapping := s.mapper.Map(base)
+
+	return code{
+		Entry:  entry,
+		Anchor: Anchor(entry.Code),
+		Kind:   entry.Kind.Code(),
+		HTTP:   fmt.Sprintf("%d %s", mapping.Status, http.StatusText(mapping.Status)),
+		GRPC:   GRPCCode(base),
+	}
+}
+
+// GRPCCode returns the name of the gRPC code of the kind, e.g. NOT_FOUND.
+// The package relies on the mapping of grpcerr.CodeOf, which is not imported,
+// since the grpcerr module depends on gRPC; the grpcerr tests check that both
+// mappings match. Custom kinds are mapped by their nearest predefined ancestor.
+// The zero Kind and custom kinds without predefined ancestors are mapped to UNKNOWN.
+func GRPCCode(kind errdetail.Kind) string {
+	codes := map[errdetail.Kind]string{
+		errdetail.ErrInvalidArgument:    "INVALID_ARGUMENT",
+		errdetail.ErrFailedPrecondition: "FAILED_PRECONDITION",
+		errdetail.ErrOutOfRange:         "OUT_OF_RANGE",
+		errdetail.ErrUnauthenticated:    "UNAUTHENTICATED",
+		errdetail.ErrPermissionDenied:   "PERMISSION_DENIED",
+		errdetail.ErrNotFound:           "NOT_FOUND",
+		errdetail.ErrAborted:            "ABORTED",
+		errdetail.ErrAlreadyExists:      "ALREADY_EXISTS",
+		errdetail.ErrRemoved:            "NOT_FOUND",
+		errdetail.ErrResourceExhausted:  "RESOURCE_EXHAUSTED",
+		errdetail.ErrDataCorrupted:      "DATA_LOSS",
+		errdetail.ErrInternal:           "INTERNAL",
+		errdetail.ErrNotImplemented:     "UNIMPLEMENTED",
+		errdetail.ErrUnavailable:        "UNAVAILABLE",
+		errdetail.ErrDeadlineExceeded:   "DEADLINE_EXCEEDED",
+		errdetail.ErrCancelled:          "CANCELLED",
+	}
+
+	code, ok := codes[kind.Resolve(func(k errdetail.Kind) bool {
+		_, ok := codes[k]
+
+		return ok
+	})]
+	if !ok {
+		return "UNKNOWN"
+	}
+
+	return code
+}
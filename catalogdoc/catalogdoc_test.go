@@ -0,0 +1,155 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package catalogdoc_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+
+	. "github.com/dnozdrin/errdetail/catalogdoc"
+)
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var errQuotaExceeded = errdetail.DefineKind("catalogdoc quota exceeded", errdetail.ErrResourceExhausted)
+
+func entries() []Entry {
+	var catalog errdetail.Catalog
+
+	catalog.Register(errdetail.CatalogEntry{
+		Code:        "email_taken",
+		Domain:      "user",
+		Description: "email is already taken",
+		Kind:        errdetail.ErrAlreadyExists,
+		HelpURL:     "https://example.com/errors.html#" + Anchor("email_taken"),
+		MetaKeys:    []string{"email"},
+	})
+	catalog.Register(errdetail.CatalogEntry{
+		Code:        "ORDER_REMOVED",
+		Domain:      "order",
+		Description: "order is <removed> | archived",
+		Kind:        errdetail.ErrRemoved,
+	})
+	catalog.Register(errdetail.CatalogEntry{
+		Code:   "quota_exceeded",
+		Domain: "user",
+		Kind:   errQuotaExceeded,
+	})
+	catalog.Register(errdetail.CatalogEntry{
+		Code: "unclassified",
+	})
+
+	return FromCatalog(catalog.Entries())
+}
+
+func TestAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"email_taken":      "email-taken",
+		"ORDER_NOT_FOUND":  "order-not-found",
+		"user.email--used": "user-email-used",
+		"_leading_":        "leading",
+	}
+
+	for code, want := range tests {
+		code, want := code, want
+
+		t.Run(code, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, want, Anchor(code))
+		})
+	}
+}
+
+func TestFromCatalog(t *testing.T) {
+	t.Parallel()
+
+	got := FromCatalog([]errdetail.CatalogEntry{{
+		Code:     "email_taken",
+		Domain:   "user",
+		Kind:     errdetail.ErrAlreadyExists,
+		HelpURL:  "https://example.com/errors#email-taken",
+		MetaKeys: []string{"email", "user_id"},
+	}})
+
+	assert.Equal(t, []Entry{{
+		Code:    "email_taken",
+		Domain:  "user",
+		Kind:    errdetail.ErrAlreadyExists,
+		HelpURL: "https://example.com/errors#email-taken",
+		Meta:    []MetaField{{Key: "email"}, {Key: "user_id"}},
+	}}, got)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		write  func(*bytes.Buffer) error
+		golden string
+	}{
+		"markdown": {
+			write: func(buf *bytes.Buffer) error {
+				return WriteMarkdown(buf, entries())
+			},
+			golden: "testdata/catalog.md",
+		},
+		"html": {
+			write: func(buf *bytes.Buffer) error {
+				return WriteHTML(buf, entries(), WithTitle("Shop <API> errors"))
+			},
+			golden: "testdata/catalog.html",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, tt.write(&buf))
+
+			expected, err := os.ReadFile(tt.golden)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), buf.String())
+		})
+	}
+
+	t.Run("mapper", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := WriteMarkdown(&buf, []Entry{{Code: "gone", Kind: errdetail.ErrNotFound}}, WithMapper(httperr.NewMapper(
+			httperr.WithMapping(errdetail.ErrNotFound, httperr.Mapping{Status: http.StatusGone, Code: "GONE"}),
+		)))
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "| [`gone`](#gone) | NOT_FOUND | 410 Gone | NOT_FOUND |  |")
+	})
+
+	t.Run("base_kind", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		err := WriteMarkdown(&buf, []Entry{{Code: "limit", Kind: "undefined limit", BaseKind: errdetail.ErrOutOfRange}})
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "| [`limit`](#limit) | UNDEFINED_LIMIT | 400 Bad Request | OUT_OF_RANGE |  |")
+	})
+}
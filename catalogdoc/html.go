@@ -0,0 +1,77 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package catalogdoc
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// WriteHTML writes an HTML reference page of the entries. Entries are
+// grouped by domains, each code has an anchor returned by Anchor.
+func WriteHTML(w io.Writer, entries []Entry, opts ...Option) error {
+	if err := htmlTemplate.Execute(w, newPage(entries, newSettings(opts))); err != nil {
+		return fmt.Errorf("write html: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:gochecknoglobals // parsed once
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- range .Domains }}
+<section>
+<h2>{{ if .Name }}Domain <code>{{ .Name }}</code>{{ else }}No domain{{ end }}</h2>
+<table>
+<thead>
+<tr><th>Code</th><th>Kind</th><th>HTTP status</th><th>gRPC code</th><th>Description</th></tr>
+</thead>
+<tbody>
+{{- range .Codes }}
+<tr><td><a href="#{{ .Anchor }}"><code>{{ .Code }}</code></a></td><td>{{ .Kind }}</td><td>{{ .HTTP }}</td><td>{{ .GRPC }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- range .Codes }}
+<article id="{{ .Anchor }}">
+<h3><code>{{ .Code }}</code></h3>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+<dl>
+<dt>Kind</dt><dd>{{ .Kind }}</dd>
+<dt>HTTP status</dt><dd>{{ .HTTP }}</dd>
+<dt>gRPC code</dt><dd>{{ .GRPC }}</dd>
+{{- if .HelpURL }}
+<dt>Help</dt><dd><a href="{{ .HelpURL }}">{{ .HelpURL }}</a></dd>
+{{- end }}
+</dl>
+{{- if .Meta }}
+<table>
+<thead>
+<tr><th>Meta key</th><th>Type</th><th>Description</th></tr>
+</thead>
+<tbody>
+{{- range .Meta }}
+<tr><td><code>{{ .Key }}</code></td><td>{{ if .Type }}<code>{{ .Type }}</code>{{ else }}-{{ end }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+</article>
+{{- end }}
+</section>
+{{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,64 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package catalogdoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// WriteMarkdown writes a Markdown reference page of the entries. Entries
+// are grouped by domains, each code has an anchor returned by Anchor.
+func WriteMarkdown(w io.Writer, entries []Entry, opts ...Option) error {
+	if err := markdownTemplate.Execute(w, newPage(entries, newSettings(opts))); err != nil {
+		return fmt.Errorf("write markdown: %w", err)
+	}
+
+	return nil
+}
+
+// escapeMarkdown escapes the text for a Markdown table cell.
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`",
+		"<", "&lt;", ">", "&gt;", "\n", " ",
+	).Replace(text)
+}
+
+//nolint:gochecknoglobals // parsed once
+var markdownTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"md": escapeMarkdown,
+}).Parse(`# {{ md .Title }}
+{{ range .Domains }}
+## {{ if .Name }}Domain ` + "`{{ .Name }}`" + `{{ else }}No domain{{ end }}
+
+| Code | Kind | HTTP status | gRPC code | Description |
+| ---- | ---- | ----------- | --------- | ----------- |
+{{- range .Codes }}
+| [` + "`{{ .Code }}`" + `](#{{ .Anchor }}) | {{ .Kind }} | {{ .HTTP }} | {{ .GRPC }} | {{ md .Description }} |
+{{- end }}
+{{ range .Codes }}
+### <a id="{{ .Anchor }}"></a>` + "`{{ .Code }}`" + `
+{{ if .Description }}
+{{ md .Description }}
+{{ end }}
+- Kind: {{ .Kind }}
+- HTTP status: {{ .HTTP }}
+- gRPC code: {{ .GRPC }}
+{{- if .HelpURL }}
+- Help: <{{ .HelpURL }}>
+{{- end }}
+{{- if .Meta }}
+
+| Meta key | Type | Description |
+| -------- | ---- | ----------- |
+{{- range .Meta }}
+| ` + "`{{ .Key }}`" + ` | {{ if .Type }}` + "`{{ .Type }}`" + `{{ else }}-{{ end }} | {{ md .Description }} |
+{{- end }}
+{{- end }}
+{{ end }}
+{{- end }}`))
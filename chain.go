@@ -0,0 +1,89 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"errors"
+)
+
+// Frame represents a single layer of a detailed error's chain.
+type Frame struct {
+	// Message is the message provided on this layer, without the messages
+	// of the underlying errors.
+	Message string
+	// Details are the details added on this layer.
+	Details []Detail
+	// Kind is the kind wrapped on this layer, if any.
+	Kind Kind
+	// Cause is the underlying error wrapped on this layer, if it is neither
+	// a detailed error nor a kind.
+	Cause error
+	// Stack is the stack trace recorded on this layer, if any.
+	Stack StackTrace
+	// Visibility is the visibility of the layer's message, see Mark.
+	Visibility Visibility
+}
+
+// Chain returns the layers of the error's chain created by New, Wrap, Join
+// and the predefined errors constructors, starting from the outermost one.
+// Errors created by other packages are not represented by separate frames.
+// For errors created by Join, the joined errors are not walked, they can be
+// inspected by calling Chain for each of them. Returns nil if there are no
+// detailed errors in the chain.
+func Chain(err error) []Frame {
+	var frames []Frame
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		switch layer := current.(type) { //nolint:errorlint // a particular layer is inspected
+		case *wrapper:
+			frames = append(frames, layer.frame())
+		case *joinError:
+			//nolint:exhaustruct // nothing else is known
+			return append(frames, Frame{Message: layer.text, Visibility: layer.visibility})
+		}
+	}
+
+	return frames
+}
+
+// frame returns the wrapper's layer representation.
+func (err *wrapper) frame() Frame {
+	frame := Frame{
+		Message:    err.text,
+		Details:    err.ownDetails(),
+		Stack:      err.stack,
+		Visibility: err.visibility,
+	}
+
+	if len(frame.Details) == 0 {
+		frame.Details = nil
+	}
+
+	switch underlying := err.underlying.(type) { //nolint:errorlint // a particular layer is inspected
+	case nil, *wrapper, *joinError:
+	case Kind:
+		frame.Kind = underlying
+	default:
+		frame.Cause = underlying
+		frame.Kind = layerKind(underlying)
+	}
+
+	return frame
+}
+
+// layerKind returns the kind found in the error's chain before the next
+// detailed layer, if any.
+func layerKind(err error) Kind {
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		switch layer := current.(type) { //nolint:errorlint // a particular layer is inspected
+		case *wrapper, *joinError:
+			return ""
+		case Kind:
+			return layer
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	downstream := NewDetail(WithDomain("user.management"), WithCode("user_not_found"))
+	own := NewDetail(WithDomain("gateway"), WithCode("order_not_found"))
+
+	repoErr := fmt.Errorf("repository: %w", assert.AnError)
+
+	tests := map[string]struct {
+		err  error
+		want []Frame
+	}{
+		"no_error": {
+			err:  nil,
+			want: nil,
+		},
+		"not_detailed": {
+			err:  repoErr,
+			want: nil,
+		},
+		"new": {
+			err: New("dummy message", own),
+			want: []Frame{
+				{Message: "dummy message", Details: []Detail{own}},
+			},
+		},
+		"predefined": {
+			err: NewNotFound("user not found", downstream),
+			want: []Frame{
+				{Message: "user not found", Details: []Detail{downstream}, Kind: ErrNotFound},
+			},
+		},
+		"predefined_wrapped_by_std_lib": {
+			err: Wrap(fmt.Errorf("storage: %w", ErrNotFound), "user not found"),
+			want: []Frame{
+				{Message: "user not found", Kind: ErrNotFound, Cause: fmt.Errorf("storage: %w", ErrNotFound)},
+			},
+		},
+		"cause": {
+			err: Wrap(repoErr, "get user", downstream),
+			want: []Frame{
+				{Message: "get user", Details: []Detail{downstream}, Cause: repoErr},
+			},
+		},
+		"layers": {
+			err: Wrap(
+				fmt.Errorf("client: %w", Wrap(NewNotFound("user not found", downstream), "")),
+				"get order",
+				own,
+			),
+			want: []Frame{
+				{
+					Message: "get order",
+					Details: []Detail{own},
+					Cause:   fmt.Errorf("client: %w", Wrap(NewNotFound("user not found", downstream), "")),
+				},
+				{Message: ""},
+				{Message: "user not found", Details: []Detail{downstream}, Kind: ErrNotFound},
+			},
+		},
+		"joined": {
+			err: Wrap(Join("validation failed", NewInvalidArgument("name is empty", downstream)), "", own),
+			want: []Frame{
+				{Message: "", Details: []Detail{own}},
+				{Message: "validation failed"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Chain(tt.err))
+		})
+	}
+}
+
+func TestChainDecoded(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithCode("dummy_code"))
+
+	tests := map[string]struct {
+		err  error
+		want []Frame
+	}{
+		"with_kind": {
+			err: NewNotFound("discount not found", detail),
+			want: []Frame{
+				{Message: "discount not found", Details: []Detail{detail}, Kind: ErrNotFound},
+			},
+		},
+		"kind_only": {
+			err: NewNotFound(""),
+			want: []Frame{
+				{Message: "", Kind: ErrNotFound},
+			},
+		},
+		"without_kind": {
+			err: New("dummy message"),
+			want: []Frame{
+				{Message: "dummy message"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(tt.err)
+			require.NoError(t, err)
+
+			decoded, err := Decode(encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, Chain(decoded))
+		})
+	}
+}
@@ -0,0 +1,454 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/catalogdoc"
+)
+
+// catalogFile is a declarative error catalog. JSON is a subset of YAML,
+// so catalogs can be written in either of them.
+type catalogFile struct {
+	Package string       `yaml:"package" json:"package"`
+	HelpURL string       `yaml:"help_url" json:"help_url"`
+	Kinds   []kindSpec   `yaml:"kinds" json:"kinds"`
+	Domains []domainSpec `yaml:"domains" json:"domains"`
+}
+
+// kindSpec describes a custom kind created by errdetail.DefineKind.
+type kindSpec struct {
+	Name   string `yaml:"name" json:"name"`
+	Parent string `yaml:"parent" json:"parent"`
+}
+
+type domainSpec struct {
+	Name  string     `yaml:"name" json:"name"`
+	Codes []codeSpec `yaml:"codes" json:"codes"`
+}
+
+type codeSpec struct {
+	Code        string     `yaml:"code" json:"code"`
+	Name        string     `yaml:"name" json:"name"`
+	Kind        string     `yaml:"kind" json:"kind"`
+	Description string     `yaml:"description" json:"description"`
+	HelpURL     string     `yaml:"help_url" json:"help_url"`
+	Meta        []metaSpec `yaml:"meta" json:"meta"`
+}
+
+type metaSpec struct {
+	Key         string `yaml:"key" json:"key"`
+	Type        string `yaml:"type" json:"type"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// kindsMu serializes definitions of the catalogs' custom kinds.
+var kindsMu sync.Mutex //nolint:gochecknoglobals // guards the process wide kinds registry
+
+// metaTypes are the Go types allowed for Meta values.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var metaTypes = map[string]bool{
+	"string":      true,
+	"bool":        true,
+	"int":         true,
+	"int64":       true,
+	"uint":        true,
+	"uint64":      true,
+	"float64":     true,
+	"interface{}": true,
+	"any":         true,
+}
+
+// parseCatalog decodes and validates a catalog file.
+func parseCatalog(r io.Reader) (*catalogFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var catalog catalogFile
+	if err := decoder.Decode(&catalog); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode catalog: %w", err)
+	}
+
+	if err := catalog.validate(); err != nil {
+		return nil, fmt.Errorf("validate catalog: %w", err)
+	}
+
+	return &catalog, nil
+}
+
+func (c *catalogFile) validate() error {
+	if c.Package != "" && !token.IsIdentifier(c.Package) {
+		return fmt.Errorf("package name %q is not a valid identifier", c.Package)
+	}
+
+	kinds := make(map[string]bool)
+
+	for _, kind := range c.Kinds {
+		if err := c.validateKind(kind, kinds); err != nil {
+			return err
+		}
+
+		kinds[kindCode(kind.Name)] = true
+	}
+
+	codes := make(map[string]bool)
+	names := make(map[string]string)
+
+	for _, domain := range c.Domains {
+		for _, code := range domain.Codes {
+			if err := c.validateCode(code, kinds); err != nil {
+				return fmt.Errorf("domain %q: %w", domain.Name, err)
+			}
+
+			if codes[code.Code] {
+				return fmt.Errorf("domain %q: code %q is duplicated", domain.Name, code.Code)
+			}
+
+			name := codeName(domain, code)
+			if other, ok := names[name]; ok {
+				return fmt.Errorf("domain %q: code %q has the same name %s as code %q", domain.Name, code.Code, name, other)
+			}
+
+			codes[code.Code] = true
+			names[name] = code.Code
+		}
+	}
+
+	return nil
+}
+
+func (c *catalogFile) validateKind(kind kindSpec, defined map[string]bool) error {
+	code := kindCode(kind.Name)
+	if code == "" {
+		return fmt.Errorf("kind %q has no letters or digits", kind.Name)
+	}
+
+	if _, ok := predefinedKind(code); ok || defined[code] {
+		return fmt.Errorf("kind %q is duplicated", kind.Name)
+	}
+
+	if _, ok := predefinedKind(kind.Parent); kind.Parent != "" && !ok && !defined[kind.Parent] {
+		return fmt.Errorf("parent kind %q of kind %q is unknown", kind.Parent, kind.Name)
+	}
+
+	return nil
+}
+
+func (c *catalogFile) validateCode(code codeSpec, kinds map[string]bool) error {
+	if code.Code == "" {
+		return errors.New("code is empty")
+	}
+
+	if code.Name != "" && !token.IsIdentifier(code.Name) {
+		return fmt.Errorf("code %q: name %q is not a valid identifier", code.Code, code.Name)
+	}
+
+	if _, ok := predefinedKind(code.Kind); code.Kind != "" && !ok && !kinds[code.Kind] {
+		return fmt.Errorf("code %q: kind %q is unknown", code.Code, code.Kind)
+	}
+
+	keys := make(map[string]bool)
+	params := make(map[string]bool)
+
+	for _, meta := range code.Meta {
+		switch {
+		case meta.Key == "":
+			return fmt.Errorf("code %q: meta key is empty", code.Code)
+		case keys[meta.Key]:
+			return fmt.Errorf("code %q: meta key %q is duplicated", code.Code, meta.Key)
+		case meta.Type != "" && !metaTypes[meta.Type]:
+			return fmt.Errorf("code %q: meta key %q has unsupported type %q", code.Code, meta.Key, meta.Type)
+		case params[paramName(meta.Key)] || paramName(meta.Key) == optsParam:
+			return fmt.Errorf("code %q: meta key %q conflicts with another parameter", code.Code, meta.Key)
+		}
+
+		keys[meta.Key] = true
+		params[paramName(meta.Key)] = true
+	}
+
+	return nil
+}
+
+// kindCode returns the code of the custom kind with the given name,
+// or an empty string if the name has no letters or digits.
+func kindCode(name string) string {
+	if camelCase(name) == "" {
+		return ""
+	}
+
+	return errdetail.Kind(name).Code()
+}
+
+// predefinedKind returns the predefined kind with the given code, if any.
+func predefinedKind(code string) (errdetail.Kind, bool) {
+	for kind := range predefinedNames() {
+		if kind.Code() == code {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// predefinedNames returns the identifiers of the predefined kinds.
+func predefinedNames() map[errdetail.Kind]string {
+	return map[errdetail.Kind]string{
+		errdetail.ErrInvalidArgument:    "ErrInvalidArgument",
+		errdetail.ErrFailedPrecondition: "ErrFailedPrecondition",
+		errdetail.ErrOutOfRange:         "ErrOutOfRange",
+		errdetail.ErrUnauthenticated:    "ErrUnauthenticated",
+		errdetail.ErrPermissionDenied:   "ErrPermissionDenied",
+		errdetail.ErrNotFound:           "ErrNotFound",
+		errdetail.ErrAborted:            "ErrAborted",
+		errdetail.ErrAlreadyExists:      "ErrAlreadyExists",
+		errdetail.ErrRemoved:            "ErrRemoved",
+		errdetail.ErrResourceExhausted:  "ErrResourceExhausted",
+		errdetail.ErrDataCorrupted:      "ErrDataCorrupted",
+		errdetail.ErrInternal:           "ErrInternal",
+		errdetail.ErrNotImplemented:     "ErrNotImplemented",
+		errdetail.ErrUnavailable:        "ErrUnavailable",
+		errdetail.ErrDeadlineExceeded:   "ErrDeadlineExceeded",
+		errdetail.ErrCancelled:          "ErrCancelled",
+	}
+}
+
+// helpURL returns the help URL of the code, either the explicit one or
+// the anchor of the code on the reference page at the catalog's help URL.
+func (c *catalogFile) helpURL(code codeSpec) string {
+	if code.HelpURL != "" || c.HelpURL == "" {
+		return code.HelpURL
+	}
+
+	return c.HelpURL + "#" + catalogdoc.Anchor(code.Code)
+}
+
+// baseKind returns the predefined kind the custom kind with the given code
+// descends from, or the predefined kind itself.
+func (c *catalogFile) baseKind(code string) errdetail.Kind {
+	for code != "" {
+		if kind, ok := predefinedKind(code); ok {
+			return kind
+		}
+
+		parent := ""
+
+		for _, kind := range c.Kinds {
+			if kindCode(kind.Name) == code {
+				parent = kind.Parent
+			}
+		}
+
+		code = parent
+	}
+
+	return ""
+}
+
+// entries converts the catalog into reference page entries.
+func (c *catalogFile) entries() []catalogdoc.Entry {
+	var entries []catalogdoc.Entry
+
+	for _, domain := range c.Domains {
+		for _, code := range domain.Codes {
+			entry := catalogdoc.Entry{
+				Code:        code.Code,
+				Domain:      domain.Name,
+				Description: code.Description,
+				BaseKind:    c.baseKind(code.Kind),
+				HelpURL:     c.helpURL(code),
+			}
+
+			if code.Kind != "" {
+				entry.Kind = c.kind(code.Kind)
+			}
+
+			for _, meta := range code.Meta {
+				entry.Meta = append(entry.Meta, catalogdoc.MetaField(meta))
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// catalogEntries converts the catalog into errdetail catalog entries.
+func (c *catalogFile) catalogEntries() []errdetail.CatalogEntry {
+	var entries []errdetail.CatalogEntry
+
+	for _, domain := range c.Domains {
+		for _, code := range domain.Codes {
+			entry := errdetail.CatalogEntry{
+				Code:        code.Code,
+				Domain:      domain.Name,
+				Description: code.Description,
+				Kind:        c.kind(code.Kind),
+				HelpURL:     c.helpURL(code),
+			}
+
+			for _, meta := range code.Meta {
+				entry.MetaKeys = append(entry.MetaKeys, meta.Key)
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// defineKinds defines the catalog's custom kinds in the current process,
+// as the generated code does, and returns them following the predefined
+// kinds. Kinds that are defined already are reused.
+func (c *catalogFile) defineKinds() []errdetail.Kind {
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+
+	var kinds, defined []errdetail.Kind
+
+	for _, kind := range errdetail.Kinds() {
+		if _, ok := predefinedKind(kind.Code()); ok {
+			kinds = append(kinds, kind)
+		} else {
+			defined = append(defined, kind)
+		}
+	}
+
+	for _, spec := range c.Kinds {
+		kind := errdetail.Kind(spec.Name)
+		if !containsKind(defined, kind) {
+			kind = errdetail.DefineKind(spec.Name, c.kind(spec.Parent))
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	return kinds
+}
+
+func containsKind(kinds []errdetail.Kind, kind errdetail.Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kind returns the kind with the given code.
+func (c *catalogFile) kind(code string) errdetail.Kind {
+	if kind, ok := predefinedKind(code); ok {
+		return kind
+	}
+
+	for _, kind := range c.Kinds {
+		if kindCode(kind.Name) == code {
+			return errdetail.Kind(kind.Name)
+		}
+	}
+
+	return ""
+}
+
+// codeName returns the Go name of the code, either the explicit one or
+// the domain name followed by the code in camel case.
+func codeName(domain domainSpec, code codeSpec) string {
+	if code.Name != "" {
+		return code.Name
+	}
+
+	return camelCase(domain.Name) + camelCase(code.Code)
+}
+
+// paramName returns the Go name of the constructor parameter for the meta key.
+func paramName(key string) string {
+	name := camelCase(key)
+	if name == "" {
+		return "value"
+	}
+
+	name = lowerFirstWord(name)
+	if token.IsKeyword(name) {
+		name += "Value"
+	}
+
+	return name
+}
+
+// lowerFirstWord lower cases the first word of a camel case name,
+// including a whole leading initialism, e.g. ID or URLPath.
+func lowerFirstWord(name string) string {
+	runes := []rune(name)
+
+	end := 1
+	for end < len(runes) && unicode.IsUpper(runes[end]) {
+		end++
+	}
+
+	if end > 1 && end < len(runes) {
+		end--
+	}
+
+	return strings.ToLower(string(runes[:end])) + string(runes[end:])
+}
+
+// initialisms are the words that are upper cased in Go names.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var initialisms = map[string]bool{
+	"API": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TLS": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// camelCase converts a name with any separators into a camel case Go name.
+// Upper case words, such as the ones of UPPER_SNAKE_CASE codes, are title cased.
+func camelCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+
+			continue
+		}
+
+		if word == strings.ToUpper(word) {
+			word = strings.ToLower(word)
+		}
+
+		runes := []rune(word)
+		b.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+
+	result := b.String()
+	if result != "" && unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+
+	return result
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"text/template"
+)
+
+const optsParam = "opts"
+
+// kindData is the template data of a custom kind.
+type kindData struct {
+	Var    string
+	Name   string
+	Parent string
+}
+
+// codeData is the template data of a code.
+type codeData struct {
+	Name        string
+	Code        string
+	Domain      string
+	Kind        string
+	Description string
+	HelpURL     string
+	Meta        []metaData
+}
+
+// metaData is the template data of a meta key.
+type metaData struct {
+	Key   string
+	Param string
+	Type  string
+}
+
+// generate writes the Go code of the catalog in the package.
+func generate(w io.Writer, catalog *catalogFile, pkg string) error {
+	data := struct {
+		Package string
+		Kinds   []kindData
+		Codes   []codeData
+	}{
+		Package: pkg,
+	}
+
+	if catalog.Package != "" {
+		data.Package = catalog.Package
+	}
+
+	if data.Package == "" {
+		return fmt.Errorf("generate: package name is not specified")
+	}
+
+	for _, kind := range catalog.Kinds {
+		parent := kindExpr(catalog, kind.Parent)
+		if parent == "" {
+			parent = `""`
+		}
+
+		data.Kinds = append(data.Kinds, kindData{
+			Var:    kindVar(kind.Name),
+			Name:   kind.Name,
+			Parent: parent,
+		})
+	}
+
+	for _, domain := range catalog.Domains {
+		for _, code := range domain.Codes {
+			data.Codes = append(data.Codes, newCodeData(catalog, domain, code))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := codeTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generate: format source: %w", err)
+	}
+
+	if _, err := w.Write(formatted); err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	return nil
+}
+
+func newCodeData(catalog *catalogFile, domain domainSpec, code codeSpec) codeData {
+	data := codeData{
+		Name:        codeName(domain, code),
+		Code:        code.Code,
+		Domain:      domain.Name,
+		Kind:        kindExpr(catalog, code.Kind),
+		Description: code.Description,
+		HelpURL:     catalog.helpURL(code),
+	}
+
+	for _, meta := range code.Meta {
+		typ := meta.Type
+		if typ == "" || typ == "any" {
+			typ = "interface{}"
+		}
+
+		data.Meta = append(data.Meta, metaData{
+			Key:   meta.Key,
+			Param: paramName(meta.Key),
+			Type:  typ,
+		})
+	}
+
+	return data
+}
+
+// kindVar returns the name of the variable that holds the custom kind.
+func kindVar(name string) string {
+	return "Err" + camelCase(name)
+}
+
+// kindExpr returns the Go expression of the kind with the given code,
+// or an empty string if there is no such kind.
+func kindExpr(catalog *catalogFile, code string) string {
+	if kind, ok := predefinedKind(code); ok {
+		return "errdetail." + predefinedNames()[kind]
+	}
+
+	for _, kind := range catalog.Kinds {
+		if kindCode(kind.Name) == code {
+			return kindVar(kind.Name)
+		}
+	}
+
+	return ""
+}
+
+//nolint:gochecknoglobals // parsed once
+var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
+	"quote":    strconv.Quote,
+	"optsName": func() string { return optsParam },
+}).Parse(`// Code generated by errdetail-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"github.com/dnozdrin/errdetail"
+)
+{{- if .Kinds }}
+
+// Custom kinds of the catalog.
+var (
+{{- range .Kinds }}
+	{{ .Var }} = errdetail.DefineKind({{ quote .Name }}, {{ .Parent }})
+{{- end }}
+)
+{{- end }}
+{{- if .Codes }}
+
+// Detail codes of the catalog.
+const (
+{{- range .Codes }}
+	// Code{{ .Name }} is the {{ quote .Code }} code of the {{ quote .Domain }} domain.
+	Code{{ .Name }} = {{ quote .Code }}
+{{- end }}
+)
+
+// Catalog entries of the detail codes, registered in the default catalog.
+var (
+{{- range .Codes }}
+	Entry{{ .Name }} = errdetail.RegisterCode(errdetail.CatalogEntry{
+		Code: Code{{ .Name }},
+		{{- if .Domain }}
+		Domain: {{ quote .Domain }},
+		{{- end }}
+		{{- if .Description }}
+		Description: {{ quote .Description }},
+		{{- end }}
+		{{- if .Kind }}
+		Kind: {{ .Kind }},
+		{{- end }}
+		{{- if .HelpURL }}
+		HelpURL: {{ quote .HelpURL }},
+		{{- end }}
+		{{- if .Meta }}
+		MetaKeys: []string{ {{- range $i, $m := .Meta }}{{ if $i }}, {{ end }}{{ quote $m.Key }}{{ end -}} },
+		{{- end }}
+	})
+{{- end }}
+)
+{{- end }}
+{{- range .Codes }}
+
+// New{{ .Name }} creates an error with the {{ quote .Code }} detail.
+{{- if .Description }}
+// The error message is the code's description.
+{{- end }}
+// The options are applied to the detail after the catalog defaults.
+{{- if .Meta }}
+// The required Meta entries are added after the options, so they are kept
+// along with the Meta set by the options.
+{{- end }}
+func New{{ .Name }}({{ range .Meta }}{{ .Param }} {{ .Type }}, {{ end }}{{ optsName }} ...errdetail.Option) error {
+{{- if .Meta }}
+	{{ optsName }} = append({{ optsName }}[:len({{ optsName }}):len({{ optsName }})], errdetail.WithMetaEntries(errdetail.Meta{
+	{{- range .Meta }}
+		{{ quote .Key }}: {{ .Param }},
+	{{- end }}
+	}))
+
+{{ end }}
+	return Entry{{ .Name }}.New({{ quote .Description }}, {{ optsName }}...)
+}
+{{- end }}
+`))
@@ -0,0 +1,198 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Command errdetail-gen generates Go code from a declarative error catalog.
+//
+// The catalog is a YAML or JSON file that lists custom kinds and domains
+// with their detail codes:
+//
+//	package: usererr
+//	kinds:
+//	  - name: quota exceeded
+//	    parent: RESOURCE_EXHAUSTED
+//	domains:
+//	  - name: user
+//	    codes:
+//	      - code: email_taken
+//	        kind: ALREADY_EXISTS
+//	        description: email is already taken
+//	        help_url: https://example.com/errors#email_taken
+//	        meta:
+//	          - key: email
+//	            type: string
+//
+// Kinds are referred to by their codes. For every detail code the command
+// generates a constant, a catalog entry registered in the default catalog
+// and a constructor that takes the code's meta values, e.g.:
+//
+//	func NewUserEmailTaken(email string, opts ...errdetail.Option) error
+//
+// Usage:
+//
+//	//go:generate go run github.com/dnozdrin/errdetail/cmd/errdetail-gen -in errors.yaml -out errors_gen.go
+//
+// The package name defaults to the one of the file that contains
+// the go:generate directive.
+//
+// The -format flag set to markdown or html makes the command generate
+// a reference page of the catalog instead of Go code:
+//
+//	errdetail-gen -in errors.yaml -format html -out errors.html
+//
+// If the catalog has the top level help_url pointing at the reference page,
+// the help URLs of the codes that have no explicit ones point at the codes'
+// anchors on the page.
+//
+// The -format flag set to openapi makes the command generate OpenAPI 3
+// components, in YAML, that describe the error responses of the renderer
+// selected by the -renderer flag: http, problem or jsonapi:
+//
+//	errdetail-gen -in errors.yaml -format openapi -renderer problem -out errors.openapi.yaml
+//
+// The components describe the renderers created with the default Mapper,
+// the openapi package allows to describe renderers with custom mappings.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dnozdrin/errdetail/catalogdoc"
+	"github.com/dnozdrin/errdetail/openapi"
+	"github.com/dnozdrin/errdetail/problem"
+)
+
+// Output formats.
+const (
+	formatGo       = "go"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatOpenAPI  = "openapi"
+)
+
+// Renderers described by the OpenAPI components.
+const (
+	rendererHTTP    = "http"
+	rendererProblem = "problem"
+	rendererJSONAPI = "jsonapi"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "errdetail-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments, reads the catalog and writes the generated code.
+// The standard input and output are used if the files are not specified.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("errdetail-gen", flag.ContinueOnError)
+	in := flags.String("in", "", "catalog `file`, YAML or JSON; standard input by default")
+	out := flags.String("out", "", "output `file`; standard output by default")
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package `name` if the catalog does not specify it")
+	format := flags.String("format", formatGo, "output `format`: go, markdown, html or openapi")
+	title := flags.String("title", catalogdoc.DefaultTitle, "reference page `title`")
+	renderer := flags.String("renderer", rendererHTTP, "`renderer` described by OpenAPI components: http, problem or jsonapi")
+	typeBase := flags.String("type-base", problem.DefaultTypeBaseURI, "`prefix` of the problem and jsonapi type URIs")
+
+	if err := flags.Parse(args); err != nil {
+		return err //nolint:wrapcheck // the flag set reports the error itself
+	}
+
+	input := stdin
+
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("open catalog: %w", err)
+		}
+		defer file.Close()
+
+		input = file
+	}
+
+	catalog, err := parseCatalog(input)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	switch *format {
+	case formatGo:
+		err = generate(&buf, catalog, *pkg)
+	case formatMarkdown:
+		err = catalogdoc.WriteMarkdown(&buf, catalog.entries(), catalogdoc.WithTitle(*title))
+	case formatHTML:
+		err = catalogdoc.WriteHTML(&buf, catalog.entries(), catalogdoc.WithTitle(*title))
+	case formatOpenAPI:
+		err = writeOpenAPI(&buf, catalog, *renderer, *typeBase)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck // errors are wrapped by the generators
+	}
+
+	if *out == "" {
+		_, err = buf.WriteTo(stdout)
+
+		return err //nolint:wrapcheck // the standard output error is returned as is
+	}
+
+	if err := os.WriteFile(*out, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+
+	return nil
+}
+
+// writeOpenAPI writes the YAML encoded OpenAPI components that describe
+// the responses of the renderer.
+func writeOpenAPI(w io.Writer, catalog *catalogFile, renderer, typeBase string) error {
+	generator := openapi.NewGenerator(
+		openapi.WithKinds(catalog.defineKinds()...),
+		openapi.WithEntries(catalog.catalogEntries()),
+		openapi.WithTypeBaseURI(typeBase),
+	)
+
+	var components openapi.Components
+
+	switch renderer {
+	case rendererHTTP:
+		components = generator.HTTP()
+	case rendererProblem:
+		components = generator.Problem()
+	case rendererJSONAPI:
+		components = generator.JSONAPI()
+	default:
+		return fmt.Errorf("unknown renderer %q", renderer)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	document := struct {
+		Components openapi.Components `yaml:"components"`
+	}{
+		Components: components,
+	}
+
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("encode components: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("encode components: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:gochecknoglobals // test flag
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		catalog string
+		args    []string
+		golden  string
+	}{
+		"yaml": {
+			catalog: "testdata/users.yaml",
+			golden:  "testdata/users.golden",
+		},
+		"json": {
+			catalog: "testdata/orders.json",
+			args:    []string{"-package", "orders"},
+			golden:  "testdata/orders.golden",
+		},
+		"markdown": {
+			catalog: "testdata/users.yaml",
+			args:    []string{"-format", "markdown"},
+			golden:  "testdata/users.md",
+		},
+		"html": {
+			catalog: "testdata/users.yaml",
+			args:    []string{"-format", "html", "-title", "Users API errors"},
+			golden:  "testdata/users.html",
+		},
+		"openapi": {
+			catalog: "testdata/users.yaml",
+			args:    []string{"-format", "openapi", "-renderer", "jsonapi", "-type-base", "https://example.com/errors/"},
+			golden:  "testdata/users.openapi.yaml",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := filepath.Join(t.TempDir(), "errors_gen.go")
+			args := append([]string{"-in", tt.catalog, "-out", out}, tt.args...)
+			require.NoError(t, run(args, nil, nil))
+
+			actual, err := os.ReadFile(out)
+			require.NoError(t, err)
+
+			if *update {
+				require.NoError(t, os.WriteFile(tt.golden, actual, 0o600))
+			}
+
+			expected, err := os.ReadFile(tt.golden)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+
+	t.Run("stdio", func(t *testing.T) {
+		t.Parallel()
+
+		catalog, err := os.ReadFile("testdata/users.yaml")
+		require.NoError(t, err)
+
+		expected, err := os.ReadFile("testdata/users.golden")
+		require.NoError(t, err)
+
+		var stdout bytes.Buffer
+		require.NoError(t, run(nil, bytes.NewReader(catalog), &stdout))
+		assert.Equal(t, string(expected), stdout.String())
+	})
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		catalog string
+		err     string
+	}{
+		"unknown_field": {
+			catalog: "package: users\ndomain: []",
+			err:     "decode catalog: yaml: unmarshal errors:\n  line 2: field domain not found in type main.catalogFile",
+		},
+		"no_package": {
+			catalog: "domains: []",
+			err:     "generate: package name is not specified",
+		},
+		"invalid_package": {
+			catalog: "package: user-errors",
+			err:     `validate catalog: package name "user-errors" is not a valid identifier`,
+		},
+		"duplicated_code": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: taken}]\n- name: b\n  codes: [{code: taken}]",
+			err:     `validate catalog: domain "b": code "taken" is duplicated`,
+		},
+		"duplicated_name": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: b_c}, {code: b.c}]",
+			err:     `validate catalog: domain "a": code "b.c" has the same name ABC as code "b_c"`,
+		},
+		"empty_code": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{description: empty}]",
+			err:     `validate catalog: domain "a": code is empty`,
+		},
+		"unknown_kind": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: b, kind: TEAPOT}]",
+			err:     `validate catalog: domain "a": code "b": kind "TEAPOT" is unknown`,
+		},
+		"duplicated_kind": {
+			catalog: "package: users\nkinds: [{name: not found}]",
+			err:     `validate catalog: kind "not found" is duplicated`,
+		},
+		"unknown_parent": {
+			catalog: "package: users\nkinds: [{name: quota, parent: LIMIT}]",
+			err:     `validate catalog: parent kind "LIMIT" of kind "quota" is unknown`,
+		},
+		"unsupported_meta_type": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: b, meta: [{key: at, type: time.Time}]}]",
+			err:     `validate catalog: domain "a": code "b": meta key "at" has unsupported type "time.Time"`,
+		},
+		"conflicting_meta": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: b, meta: [{key: user_id}, {key: user-id}]}]",
+			err:     `validate catalog: domain "a": code "b": meta key "user-id" conflicts with another parameter`,
+		},
+		"invalid_name": {
+			catalog: "package: users\ndomains:\n- name: a\n  codes: [{code: b, name: 1b}]",
+			err:     `validate catalog: domain "a": code "b": name "1b" is not a valid identifier`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout bytes.Buffer
+
+			err := run([]string{"-package", ""}, strings.NewReader(tt.catalog), &stdout)
+			assert.EqualError(t, err, tt.err)
+			assert.Empty(t, stdout.String())
+		})
+	}
+
+	t.Run("unknown_format", func(t *testing.T) {
+		t.Parallel()
+
+		err := run([]string{"-format", "pdf"}, strings.NewReader("package: users"), nil)
+		assert.EqualError(t, err, `unknown format "pdf"`)
+	})
+
+	t.Run("unknown_renderer", func(t *testing.T) {
+		t.Parallel()
+
+		err := run([]string{"-format", "openapi", "-renderer", "grpc"}, strings.NewReader("package: users"), nil)
+		assert.EqualError(t, err, `unknown renderer "grpc"`)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, run([]string{"-in", "testdata/missing.yaml"}, nil, nil))
+	})
+}
+
+func TestParamName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"email":      "email",
+		"max_length": "maxLength",
+		"user-id":    "userID",
+		"id":         "id",
+		"url_path":   "urlPath",
+		"type":       "typeValue",
+		"HTTPStatus": "httpStatus",
+		"USER_ID":    "userID",
+		"2fa":        "n2fa",
+		"!!":         "value",
+	}
+
+	for key, want := range tests {
+		key, want := key, want
+
+		t.Run(key, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, want, paramName(key))
+		})
+	}
+}
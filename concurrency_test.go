@@ -0,0 +1,81 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+// TestConcurrentAccess is intended to be run with the race detector.
+// It shares an error between goroutines that read it in every supported
+// way, while the caller keeps modifying the Meta the error was created with.
+func TestConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	const goroutines = 8
+
+	meta := Meta{"attempt": 1, "tags": []interface{}{"a"}}
+	detail := NewDetail(WithCode("dummy_code"), WithField("dummy_field"), WithMeta(meta))
+	err := Wrap(NewNotFound("user not found", detail), "get user", NewDetail(WithMeta(meta)))
+	joined := Join("batch failed", err, NewInternal("dummy message", detail))
+
+	var wg sync.WaitGroup
+
+	wg.Add(goroutines + 1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			meta["attempt"] = i
+			meta["tags"] = append(meta["tags"].([]interface{}), i) //nolint:forcetypeassert // known test data
+		}
+	}()
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				_ = err.Error()
+				_ = fmt.Sprintf("%+v %#v", err, joined)
+				_, _ = json.Marshal(joined)
+				_ = Chain(err)
+				_ = KindOf(err)
+
+				details := append(ExtractDetails(err), detail)
+				details[0] = detail
+
+				details = append(ExtractDetails(joined), detail)
+				details[0] = NewDetail(WithCode("other_code"))
+				ExtractDetails(joined)[1] = NewDetail(WithCode("other_code"))
+
+				for _, d := range ExtractAllDetails(joined) {
+					d.Meta()["attempt"] = j
+					_, _ = MetaValue[int](d, "attempt")
+					_ = d.Clone()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, d := range ExtractAllDetails(joined) {
+		assert.Equal(t, Meta{"attempt": 1, "tags": []interface{}{"a"}}, d.Meta())
+	}
+
+	assert.Equal(t, "dummy_code", ExtractDetails(joined)[0].Code(), "details are not modified")
+	assert.Empty(t, ExtractDetails(joined)[1].Code(), "details are not modified")
+	assert.Len(t, ExtractDetails(err), 2)
+	assert.Equal(t, len(ExtractDetails(err)), cap(ExtractDetails(err)))
+}
@@ -6,20 +6,32 @@ package errdetail
 
 import (
 	"errors"
+	"reflect"
 )
 
 // Detail represents a set of optional fields which provide more
 // information about a parent error.
 type Detail struct {
-	field       string
-	description string
-	code        string
-	domain      string
-	reason      string
-	meta        Meta
-	filled      bool
+	field               string
+	description         string
+	descriptionTemplate string
+	code                string
+	domain              string
+	reason              string
+	reasonTemplate      string
+	templateArgs        Meta
+	helpURL             string
+	messageID           string
+	messageArgs         Meta
+	meta                Meta
+	payload             interface{}
+	visibility          Visibility
+	severity            Severity
+	redacted            bool
+	filled              bool
 }
 
+// Meta represents arbitrary data of a Detail.
 type Meta map[string]interface{}
 
 // Field is a Detail field getter.
@@ -28,7 +40,12 @@ func (d *Detail) Field() string {
 }
 
 // Description is a Detail description getter.
+// A description template is rendered with the template arguments.
 func (d *Detail) Description() string {
+	if d.descriptionTemplate != "" {
+		return renderTemplate(d.descriptionTemplate, d.templateArgs)
+	}
+
 	return d.description
 }
 
@@ -44,13 +61,35 @@ func (d *Detail) Domain() string {
 }
 
 // Reason is a Detail reason getter.
+// A reason template is rendered with the template arguments.
 func (d *Detail) Reason() string {
+	if d.reasonTemplate != "" {
+		return renderTemplate(d.reasonTemplate, d.templateArgs)
+	}
+
 	return d.reason
 }
 
+// HelpURL is a Detail documentation link getter.
+func (d *Detail) HelpURL() string {
+	return d.helpURL
+}
+
 // Meta is a Detail arbitrary data getter.
+// It returns a copy, so the detail can not be modified by the caller.
 func (d *Detail) Meta() Meta {
-	return d.meta
+	return d.meta.clone()
+}
+
+// Clone returns a copy of the detail that shares no Meta maps and slices
+// with the original one, including the message and the template arguments.
+// The payload is shared, since it is not modified by the package.
+func (d Detail) Clone() Detail {
+	d.meta = d.meta.clone()
+	d.messageArgs = d.messageArgs.clone()
+	d.templateArgs = d.templateArgs.clone()
+
+	return d
 }
 
 // NewDetail represents a Detail constructor.
@@ -78,10 +117,12 @@ func WithField(field string) Option {
 }
 
 // WithDescription is an option for Detail constructs that sets
-// description and marks the detail as not empty.
+// description and marks the detail as not empty. The option overrides
+// WithDescriptionTemplate.
 func WithDescription(description string) Option {
 	return func(d *Detail) {
 		d.description = description
+		d.descriptionTemplate = ""
 		if d.description != "" {
 			d.filled = true
 		}
@@ -112,27 +153,66 @@ func WithDomain(domain string) Option {
 }
 
 // WithReason is an option for Detail constructs that sets an error
-// reason and marks the detail as not empty.
+// reason and marks the detail as not empty. The option overrides
+// WithReasonTemplate.
 func WithReason(reason string) Option {
 	return func(d *Detail) {
 		d.reason = reason
+		d.reasonTemplate = ""
 		if d.reason != "" {
 			d.filled = true
 		}
 	}
 }
 
+// WithHelpURL is an option for Detail constructs that sets a link
+// to the error documentation and marks the detail as not empty.
+func WithHelpURL(url string) Option {
+	return func(d *Detail) {
+		d.helpURL = url
+		if d.helpURL != "" {
+			d.filled = true
+		}
+	}
+}
+
 // WithMeta is an option for Detail constructs that sets an error
-// arbitrary data and marks the detail as not empty.
+// arbitrary data and marks the detail as not empty. The data is copied,
+// including nested maps and slices, so later changes of the caller's map
+// do not affect the detail.
 func WithMeta(meta Meta) Option {
 	return func(d *Detail) {
-		d.meta = meta
+		d.meta = meta.clone()
 		if d.meta != nil {
 			d.filled = true
 		}
 	}
 }
 
+// WithMetaEntries is an option for Detail constructs that adds the entries
+// to the error arbitrary data, replacing the entries with the same keys, and
+// marks the detail as not empty. The entries are copied the same way as by
+// WithMeta.
+func WithMetaEntries(entries Meta) Option {
+	return func(d *Detail) {
+		if len(entries) == 0 {
+			return
+		}
+
+		merged := d.meta.clone()
+		if merged == nil {
+			merged = make(Meta, len(entries))
+		}
+
+		for key, value := range entries.clone() {
+			merged[key] = value
+		}
+
+		d.meta = merged
+		d.filled = true
+	}
+}
+
 type detailed interface {
 	Details() []Detail
 }
@@ -146,3 +226,58 @@ func ExtractDetails(err error) []Detail {
 
 	return nil
 }
+
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+// ExtractAllDetails extracts details from the whole error's tree, including
+// errors joined by Join, errors.Join and fmt.Errorf with multiple %w verbs.
+// Repeated details are returned once. If there are no details, returns nil.
+func ExtractAllDetails(err error) []Detail {
+	collected := collectDetails(err)
+
+	var result []Detail
+
+	for i := range collected {
+		if !containsDetail(result, collected[i]) {
+			result = append(result, collected[i])
+		}
+	}
+
+	return result
+}
+
+// collectDetails walks the error's tree and collects details of the detailed
+// errors found. The tree below a detailed error is not walked, since its
+// details already include the details of its underlying errors.
+func collectDetails(err error) []Detail {
+	for err != nil {
+		if d, ok := err.(detailed); ok { //nolint:errorlint // a particular layer is inspected
+			return d.Details()
+		}
+
+		if multi, ok := err.(multiUnwrapper); ok { //nolint:errorlint // a particular layer is inspected
+			var details []Detail
+			for _, branch := range multi.Unwrap() {
+				details = append(details, collectDetails(branch)...)
+			}
+
+			return details
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return nil
+}
+
+func containsDetail(details []Detail, detail Detail) bool {
+	for i := range details {
+		if reflect.DeepEqual(details[i], detail) {
+			return true
+		}
+	}
+
+	return false
+}
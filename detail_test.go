@@ -25,6 +25,7 @@ func TestDetail(t *testing.T) {
 		code        string
 		domain      string
 		reason      string
+		helpURL     string
 		meta        Meta
 	}
 
@@ -40,6 +41,7 @@ func TestDetail(t *testing.T) {
 					WithCode("code_1"),
 					WithDomain("domain_1"),
 					WithReason("reason_1"),
+					WithHelpURL("https://example.com/errors#code_1"),
 					WithMeta(Meta{
 						"dummyField1": 1,
 						"dummyField2": "dummyValue",
@@ -56,6 +58,7 @@ func TestDetail(t *testing.T) {
 				code:        "code_1",
 				domain:      "domain_1",
 				reason:      "reason_1",
+				helpURL:     "https://example.com/errors#code_1",
 				meta: Meta{
 					"dummyField1": 1,
 					"dummyField2": "dummyValue",
@@ -93,11 +96,128 @@ func TestDetail(t *testing.T) {
 			assert.Equal(t, tt.values.code, detail.Code())
 			assert.Equal(t, tt.values.domain, detail.Domain())
 			assert.Equal(t, tt.values.reason, detail.Reason())
+			assert.Equal(t, tt.values.helpURL, detail.HelpURL())
 			assert.Equal(t, tt.values.meta, detail.Meta())
 		})
 	}
 }
 
+func TestDetail_Meta(t *testing.T) {
+	t.Parallel()
+
+	newMeta := func() Meta {
+		return Meta{
+			"id":     "42",
+			"limits": map[string]int{"daily": 100},
+			"tags":   []interface{}{"a", Meta{"nested": true}},
+			"empty":  nil,
+		}
+	}
+
+	tests := map[string]struct {
+		mutate func(original Meta, detail *Detail)
+	}{
+		"caller_map": {
+			mutate: func(original Meta, _ *Detail) {
+				original["id"] = "43"
+				original["limits"].(map[string]int)["daily"] = 0 //nolint:forcetypeassert // known test data
+				original["tags"].([]interface{})[1] = "b"        //nolint:forcetypeassert // known test data
+				delete(original, "empty")
+			},
+		},
+		"returned_map": {
+			mutate: func(_ Meta, detail *Detail) {
+				meta := detail.Meta()
+				meta["id"] = "43"
+				meta["tags"].([]interface{})[1].(Meta)["nested"] = false //nolint:forcetypeassert // known test data
+			},
+		},
+		"clone": {
+			mutate: func(_ Meta, detail *Detail) {
+				cloned := detail.Clone()
+				meta := cloned.Meta()
+				meta["id"] = "43"
+
+				WithMeta(meta)(&cloned)
+				assert.Equal(t, "43", cloned.Meta()["id"])
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			original := newMeta()
+			detail := NewDetail(WithMeta(original))
+
+			tt.mutate(original, &detail)
+
+			assert.Equal(t, newMeta(), detail.Meta())
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		detail := NewDetail(WithCode("dummy_code"))
+
+		assert.Nil(t, detail.Meta())
+		assert.Equal(t, detail, detail.Clone())
+	})
+}
+
+func TestWithMetaEntries(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts []Option
+		want Detail
+	}{
+		"merge": {
+			opts: []Option{WithMeta(Meta{"id": "42", "attempt": 1}), WithMetaEntries(Meta{"id": "43", "email": "a@b.c"})},
+			want: NewDetail(WithMeta(Meta{"id": "43", "attempt": 1, "email": "a@b.c"})),
+		},
+		"no_meta": {
+			opts: []Option{WithCode("dummy_code"), WithMetaEntries(Meta{"id": "42"})},
+			want: NewDetail(WithCode("dummy_code"), WithMeta(Meta{"id": "42"})),
+		},
+		"empty": {
+			opts: []Option{WithMeta(Meta{"id": "42"}), WithMetaEntries(nil), WithMetaEntries(Meta{})},
+			want: NewDetail(WithMeta(Meta{"id": "42"})),
+		},
+		"not_filled": {
+			opts: []Option{WithMetaEntries(nil)},
+			want: Detail{},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, NewDetail(tt.opts...))
+		})
+	}
+
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+
+		original := Meta{"id": "42"}
+		entries := Meta{"tags": []interface{}{"a"}}
+		detail := NewDetail(WithMeta(original), WithMetaEntries(entries))
+
+		original["id"] = "43"
+		entries["tags"].([]interface{})[0] = "b" //nolint:forcetypeassert // known test data
+
+		assert.Equal(t, Meta{"id": "42", "tags": []interface{}{"a"}}, detail.Meta())
+	})
+}
+
 func TestExtractDetails(t *testing.T) {
 	t.Parallel()
 
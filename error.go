@@ -7,34 +7,72 @@ package errdetail
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // New allows to create unspecified errors with details provided.
 func New(msg string, details ...Detail) error {
-	return &wrapper{
-		msg:        msg,
-		underlying: nil,
-		details:    filter(details),
-	}
+	return wrap(nil, msg, details)
 }
 
 // Wrap allows to wrap errors and add details to them.
 func Wrap(err error, msg string, details ...Detail) error {
 	if err != nil {
-		wrapped := &wrapper{underlying: err}
+		return wrap(err, msg, details)
+	}
+
+	return nil
+}
+
+// Restore allows to recreate errors received from other services. Unlike Wrap,
+// it keeps the message as is, the message is expected to include the kind's
+// message already, as Wrap does. The kind may be zero, if it is unknown.
+func Restore(msg string, kind Kind, details ...Detail) error {
+	restored := &wrapper{
+		msg:     msg,
+		text:    msg,
+		details: filter(details),
+	}
 
-		if msg != "" {
-			wrapped.msg = fmt.Sprintf("%s: %s", msg, err.Error())
-		} else {
-			wrapped.msg = err.Error()
+	if kind != "" {
+		restored.underlying = kind
+		restored.text = strings.TrimSuffix(msg, ": "+kind.Error())
+
+		if msg == kind.Error() {
+			restored.text = ""
 		}
+	}
+
+	return restored
+}
+
+// wrap creates a detailed error. It must be called directly by the exported
+// constructors only, so the stack trace is recorded from their caller.
+func wrap(err error, msg string, details []Detail) *wrapper {
+	wrapped := &wrapper{
+		msg:        msg,
+		text:       msg,
+		underlying: err,
+		stack:      callers(err),
+	}
 
-		wrapped.details = filter(ExtractDetails(err), details)
+	if err == nil {
+		wrapped.details = filter(details)
 
 		return wrapped
 	}
 
-	return nil
+	if msg != "" {
+		wrapped.msg = fmt.Sprintf("%s: %s", msg, err.Error())
+	} else {
+		wrapped.msg = err.Error()
+	}
+
+	inherited := filter(collectDetails(err))
+	wrapped.details = filter(inherited, details)
+	wrapped.inherited = len(inherited)
+
+	return wrapped
 }
 
 func filter(src ...[]Detail) []Detail {
@@ -59,13 +97,32 @@ func filter(src ...[]Detail) []Detail {
 		return nil
 	}
 
-	return result[:n]
+	return result[:n:n]
+}
+
+// copyDetails returns a copy of the details, so the caller can not modify
+// the details of an error. Returns nil if there are no details.
+func copyDetails(details []Detail) []Detail {
+	if len(details) == 0 {
+		return nil
+	}
+
+	return append(make([]Detail, 0, len(details)), details...)
 }
 
 type wrapper struct {
+	// msg is the full error message, text is the message passed to the
+	// constructor, without the underlying error's message.
 	msg        string
+	text       string
 	underlying error
-	details    []Detail
+	// details include the underlying error's details followed by the details
+	// added on this layer, inherited is the number of the former ones.
+	details   []Detail
+	inherited int
+	stack     StackTrace
+	// visibility is the visibility of text.
+	visibility Visibility
 }
 
 // Error returns error message.
@@ -84,6 +141,24 @@ func (err *wrapper) Unwrap() error {
 }
 
 // Details returns the wrapped error's details.
+// It returns a copy, so the error can not be modified by the caller.
 func (err *wrapper) Details() []Detail {
-	return err.details
+	return copyDetails(err.details)
+}
+
+// StackTrace returns the stack trace recorded at the error creation.
+// If the stack trace has been recorded for an underlying error,
+// it is returned instead.
+func (err *wrapper) StackTrace() StackTrace {
+	if err.stack == nil {
+		return ExtractStackTrace(err.underlying)
+	}
+
+	return err.stack
+}
+
+// ownDetails returns the details added on the wrapper's layer, skipping
+// the ones copied from the underlying error.
+func (err *wrapper) ownDetails() []Detail {
+	return err.details[err.inherited:]
 }
@@ -398,3 +398,52 @@ func TestChainedWrap(t *testing.T) {
 		assert.ErrorIs(t, err2, err1)
 	})
 }
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithCode("dummy_code"))
+
+	tests := map[string]struct {
+		msg   string
+		kind  Kind
+		frame Frame
+	}{
+		"with_kind": {
+			msg:   "dummy message: not found",
+			kind:  ErrNotFound,
+			frame: Frame{Message: "dummy message", Details: []Detail{detail}, Kind: ErrNotFound},
+		},
+		"kind_only": {
+			msg:   "not found",
+			kind:  ErrNotFound,
+			frame: Frame{Message: "", Details: []Detail{detail}, Kind: ErrNotFound},
+		},
+		"foreign_message": {
+			msg:   "dummy message",
+			kind:  ErrNotFound,
+			frame: Frame{Message: "dummy message", Details: []Detail{detail}, Kind: ErrNotFound},
+		},
+		"no_kind": {
+			msg:   "dummy message",
+			kind:  "",
+			frame: Frame{Message: "dummy message", Details: []Detail{detail}},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Restore(tt.msg, tt.kind, detail, NewDetail())
+			require.Error(t, err)
+
+			assert.EqualError(t, err, tt.msg)
+			assert.Equal(t, tt.kind, KindOf(err))
+			assert.Equal(t, []Detail{detail}, ExtractDetails(err))
+			assert.Equal(t, []Frame{tt.frame}, Chain(err))
+		})
+	}
+}
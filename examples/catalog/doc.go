@@ -0,0 +1,10 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package catalog provides an example of generating detailed errors
+// from a declarative catalog, see errors.yaml.
+package catalog
+
+//go:generate go run github.com/dnozdrin/errdetail/cmd/errdetail-gen -in errors.yaml -out errors_gen.go
+//go:generate go run github.com/dnozdrin/errdetail/cmd/errdetail-gen -in errors.yaml -out errors.md -format markdown -title "Example errors"
@@ -0,0 +1,89 @@
+// Code generated by errdetail-gen. DO NOT EDIT.
+
+package catalog
+
+import (
+	"github.com/dnozdrin/errdetail"
+)
+
+// Custom kinds of the catalog.
+var (
+	ErrQuotaExceeded = errdetail.DefineKind("quota exceeded", errdetail.ErrResourceExhausted)
+)
+
+// Detail codes of the catalog.
+const (
+	// CodeUserEmailTaken is the "email_taken" code of the "user" domain.
+	CodeUserEmailTaken = "email_taken"
+	// CodeUserWeakPassword is the "weak_password" code of the "user" domain.
+	CodeUserWeakPassword = "weak_password"
+	// CodeBillingQuotaExceeded is the "quota_exceeded" code of the "billing" domain.
+	CodeBillingQuotaExceeded = "quota_exceeded"
+)
+
+// Catalog entries of the detail codes, registered in the default catalog.
+var (
+	EntryUserEmailTaken = errdetail.RegisterCode(errdetail.CatalogEntry{
+		Code:        CodeUserEmailTaken,
+		Domain:      "user",
+		Description: "email is already taken",
+		Kind:        errdetail.ErrAlreadyExists,
+		HelpURL:     "https://example.com/errors#email-taken",
+		MetaKeys:    []string{"email"},
+	})
+	EntryUserWeakPassword = errdetail.RegisterCode(errdetail.CatalogEntry{
+		Code:        CodeUserWeakPassword,
+		Domain:      "user",
+		Description: "password is too weak",
+		Kind:        errdetail.ErrInvalidArgument,
+		HelpURL:     "https://example.com/errors#weak-password",
+		MetaKeys:    []string{"min_length"},
+	})
+	EntryBillingQuotaExceeded = errdetail.RegisterCode(errdetail.CatalogEntry{
+		Code:        CodeBillingQuotaExceeded,
+		Domain:      "billing",
+		Description: "request quota is exceeded",
+		Kind:        ErrQuotaExceeded,
+		HelpURL:     "https://example.com/errors#quota-exceeded",
+		MetaKeys:    []string{"limit"},
+	})
+)
+
+// NewUserEmailTaken creates an error with the "email_taken" detail.
+// The error message is the code's description.
+// The options are applied to the detail after the catalog defaults.
+// The required Meta entries are added after the options, so they are kept
+// along with the Meta set by the options.
+func NewUserEmailTaken(email string, opts ...errdetail.Option) error {
+	opts = append(opts[:len(opts):len(opts)], errdetail.WithMetaEntries(errdetail.Meta{
+		"email": email,
+	}))
+
+	return EntryUserEmailTaken.New("email is already taken", opts...)
+}
+
+// NewUserWeakPassword creates an error with the "weak_password" detail.
+// The error message is the code's description.
+// The options are applied to the detail after the catalog defaults.
+// The required Meta entries are added after the options, so they are kept
+// along with the Meta set by the options.
+func NewUserWeakPassword(minLength int, opts ...errdetail.Option) error {
+	opts = append(opts[:len(opts):len(opts)], errdetail.WithMetaEntries(errdetail.Meta{
+		"min_length": minLength,
+	}))
+
+	return EntryUserWeakPassword.New("password is too weak", opts...)
+}
+
+// NewBillingQuotaExceeded creates an error with the "quota_exceeded" detail.
+// The error message is the code's description.
+// The options are applied to the detail after the catalog defaults.
+// The required Meta entries are added after the options, so they are kept
+// along with the Meta set by the options.
+func NewBillingQuotaExceeded(limit int, opts ...errdetail.Option) error {
+	opts = append(opts[:len(opts):len(opts)], errdetail.WithMetaEntries(errdetail.Meta{
+		"limit": limit,
+	}))
+
+	return EntryBillingQuotaExceeded.New("request quota is exceeded", opts...)
+}
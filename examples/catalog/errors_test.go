@@ -0,0 +1,86 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package catalog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dnozdrin/errdetail"
+
+	. "github.com/dnozdrin/errdetail/examples/catalog"
+)
+
+func TestGeneratedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err    error
+		kind   errdetail.Kind
+		text   string
+		detail errdetail.Detail
+	}{
+		"email_taken": {
+			err:  NewUserEmailTaken("user@example.com", errdetail.WithField("user.email")),
+			kind: errdetail.ErrAlreadyExists,
+			text: "email is already taken: already exists",
+			detail: errdetail.NewDetail(
+				errdetail.WithCode(CodeUserEmailTaken),
+				errdetail.WithDomain("user"),
+				errdetail.WithDescription("email is already taken"),
+				errdetail.WithHelpURL("https://example.com/errors#email-taken"),
+				errdetail.WithMeta(errdetail.Meta{"email": "user@example.com"}),
+				errdetail.WithField("user.email"),
+			),
+		},
+		"caller_meta": {
+			err: NewUserEmailTaken("user@example.com",
+				errdetail.WithMeta(errdetail.Meta{"email": "other@example.com", "attempt": 2})),
+			kind: errdetail.ErrAlreadyExists,
+			text: "email is already taken: already exists",
+			detail: errdetail.NewDetail(
+				errdetail.FromCatalog(CodeUserEmailTaken),
+				errdetail.WithMeta(errdetail.Meta{"email": "user@example.com", "attempt": 2}),
+			),
+		},
+		"quota_exceeded": {
+			err:  NewBillingQuotaExceeded(100),
+			kind: ErrQuotaExceeded,
+			text: "request quota is exceeded: quota exceeded",
+			detail: errdetail.NewDetail(
+				errdetail.FromCatalog(CodeBillingQuotaExceeded),
+				errdetail.WithMeta(errdetail.Meta{"limit": 100}),
+			),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tt.err, tt.text)
+			assert.Equal(t, tt.kind, errdetail.KindOf(tt.err))
+			assert.Equal(t, []errdetail.Detail{tt.detail}, errdetail.ExtractDetails(tt.err))
+			assert.NoError(t, errdetail.ValidateDetail(errdetail.ExtractDetails(tt.err)[0]))
+		})
+	}
+
+	assert.True(t, errors.Is(NewBillingQuotaExceeded(100), errdetail.ErrResourceExhausted))
+}
+
+func TestCatalogEntries(t *testing.T) {
+	t.Parallel()
+
+	codes := make([]string, 0, 3)
+	for _, entry := range errdetail.CatalogEntries() {
+		codes = append(codes, entry.Code)
+	}
+
+	assert.Equal(t, []string{CodeUserEmailTaken, CodeUserWeakPassword, CodeBillingQuotaExceeded}, codes)
+}
@@ -0,0 +1,18 @@
+module github.com/dnozdrin/errdetail/examples/catalog
+
+go 1.18
+
+replace github.com/dnozdrin/errdetail => ../..
+
+require (
+	github.com/dnozdrin/errdetail v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.1
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,12 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+//go:build tools
+// +build tools
+
+package catalog
+
+import (
+	_ "github.com/dnozdrin/errdetail/cmd/errdetail-gen"
+)
@@ -0,0 +1,49 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dnozdrin/errdetail"
+	render "github.com/dnozdrin/errdetail/jsonapi"
+)
+
+// This package provides examples of formatting detailed errors
+// according to the JSON:API Specification.
+// For details see https://jsonapi.org/format/#error-objects.
+
+// renderer adds an about link to every detail that has a code.
+var renderer = render.NewRenderer( //nolint:gochecknoglobals // immutable after creation
+	render.WithAboutLinks(func(detail errdetail.Detail) string {
+		if detail.Code() == "" {
+			return ""
+		}
+
+		return "https://example.com/errors/" + detail.Code()
+	}),
+)
+
+// Action handles a request and returns either a result or an error.
+type Action func(r *http.Request) (interface{}, error)
+
+// NewHandler creates an HTTP handler that writes the action's result as
+// a JSON:API document. Errors returned by the action are rendered as
+// JSON:API error objects.
+func NewHandler(action Action) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := action(r)
+		if err != nil {
+			renderer.WriteError(w, r, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", render.ContentType)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": result})
+	})
+}
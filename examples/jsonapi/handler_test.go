@@ -5,36 +5,41 @@
 package jsonapi_test
 
 import (
-	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	. "github.com/dnozdrin/errdetail/examples/json_api"
-
 	"github.com/dnozdrin/errdetail"
+	render "github.com/dnozdrin/errdetail/jsonapi"
+
+	. "github.com/dnozdrin/errdetail/examples/json_api"
 )
 
-func TestNewErrorResponse(t *testing.T) {
+func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
-		err  error
-		file string
+		err    error
+		status int
+		file   string
 	}{
 		"no_error": {
-			err:  nil,
-			file: "no_error",
+			err:    nil,
+			status: http.StatusOK,
+			file:   "no_error",
 		},
 		"no_details": {
-			err:  assert.AnError,
-			file: "no_error",
+			err:    assert.AnError,
+			status: http.StatusInternalServerError,
+			file:   "no_details",
 		},
 		"multiple_errors": {
 			err: errdetail.Wrap(
-				assert.AnError,
+				errdetail.ErrInvalidArgument,
 				"let's do it again, %username%, everything is garbage",
 				errdetail.NewDetail(
 					errdetail.WithDomain("user.management"),
@@ -47,14 +52,15 @@ func TestNewErrorResponse(t *testing.T) {
 					errdetail.WithCode("admin_required"),
 					errdetail.WithDescription("Permission denied"),
 					errdetail.WithReason("Editing secret powers is not authorized on Sundays."),
+					errdetail.WithMeta(errdetail.Meta{"day": "Sunday"}),
 				),
 				errdetail.NewDetail(
-					errdetail.WithCode("internal"),
 					errdetail.WithDescription("The backend responded with an error"),
 					errdetail.WithReason("Reputation service not responding after three requests."),
 				),
 			),
-			file: "multiple_errors",
+			status: http.StatusBadRequest,
+			file:   "multiple_errors",
 		},
 	}
 	for name, tt := range tests {
@@ -62,15 +68,21 @@ func TestNewErrorResponse(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			got := NewErrorResponse(tt.err)
 
-			actual, err := json.Marshal(got)
-			require.NoError(t, err)
+			handler := NewHandler(func(*http.Request) (interface{}, error) {
+				return map[string]string{"type": "users", "id": "1"}, tt.err
+			})
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, render.ContentType, recorder.Header().Get("Content-Type"))
 
 			expected, err := os.ReadFile("testdata/" + tt.file + ".json")
 			require.NoError(t, err)
 
-			assert.JSONEq(t, string(expected), string(actual))
+			assert.JSONEq(t, string(expected), recorder.Body.String())
 		})
 	}
 }
@@ -0,0 +1,30 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package simple_http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dnozdrin/errdetail/httperr"
+)
+
+// Action handles a request and returns either a result or an error.
+type Action func(r *http.Request) (interface{}, error)
+
+// NewHandler creates an HTTP handler that writes the action's result encoded
+// to JSON. Errors returned by the action are rendered by the httperr package.
+func NewHandler(action Action) http.Handler {
+	return httperr.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		result, err := action(r)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -6,8 +6,9 @@ package simple_http_test
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -19,7 +20,10 @@ import (
 	. "github.com/dnozdrin/errdetail/examples/simple_http"
 )
 
-func TestNewErrorResponse(t *testing.T) {
+// fixtures is the directory of the expected responses, shared with the httperr package tests.
+const fixtures = "../../httperr/testdata/"
+
+func TestNewHandler(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
@@ -391,15 +395,19 @@ func TestNewErrorResponse(t *testing.T) {
 
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			got := NewErrorResponse(tt.err)
+			handler := NewHandler(func(r *http.Request) (interface{}, error) {
+				return struct{}{}, tt.err
+			})
 
-			actual, err := json.Marshal(got)
-			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			actual := rec.Body.String()
 
-			expected, err := os.ReadFile("testdata/" + tt.file + ".json")
+			expected, err := os.ReadFile(fixtures + tt.file + ".json")
 			require.NoError(t, err)
 
-			assert.JSONEq(t, string(expected), string(actual))
+			assert.JSONEq(t, string(expected), actual)
 		})
 	}
 }
@@ -0,0 +1,148 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	detailIndent = "    "
+	fieldIndent  = detailIndent + "  "
+	metaIndent   = fieldIndent + "  "
+)
+
+// Format is the `fmt.Formatter` interface implementation for errors
+// created by New, Wrap and the predefined errors constructors.
+//
+// The verbs %s and %v print the error message, %q prints the quoted
+// error message. The %+v verb prints each layer of the error's chain
+// followed by the details added on that layer and the stack trace
+// recorded on that layer, if any. The %#v verb prints a Go-syntax
+// representation of the error. The messages and the details are redacted
+// by the package Redactor in every verb, see SetRedactor.
+func (err *wrapper) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			writeLayers(s, err)
+		case s.Flag('#'):
+			fmt.Fprintf(s, "&errdetail.wrapper{msg:%q, underlying:%s, details:%#v}",
+				RedactText(err.msg), RedactText(fmt.Sprintf("%#v", err.underlying)), RedactDetails(err.details))
+		default:
+			_, _ = io.WriteString(s, RedactText(err.msg))
+		}
+	case 's':
+		_, _ = io.WriteString(s, RedactText(err.msg))
+	case 'q':
+		fmt.Fprintf(s, "%q", RedactText(err.msg))
+	default:
+		fmt.Fprintf(s, "%%!%c(*errdetail.wrapper=%s)", verb, RedactText(err.msg))
+	}
+}
+
+// writeLayers writes the message, the own details and the stack trace of
+// every detailed layer in the error's chain. Layers added by other packages
+// are skipped, since their messages are a part of the outer layers' messages,
+// except for the root cause of the chain. If the chain branches, each branch
+// is written indented. The messages are redacted by the package Redactor.
+func writeLayers(w io.Writer, err error) {
+	var separator string
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if multi, ok := current.(multiUnwrapper); ok { //nolint:errorlint // a particular layer is inspected
+			if joined, ok := current.(*joinError); ok { //nolint:errorlint // a particular layer is inspected
+				_, _ = io.WriteString(w, separator+RedactText(joined.msg))
+				separator = "\n"
+			}
+
+			for _, branch := range multi.Unwrap() {
+				var buf strings.Builder
+
+				writeLayers(&buf, branch)
+				fmt.Fprintf(w, "%s%s%s", separator, detailIndent,
+					strings.ReplaceAll(buf.String(), "\n", "\n"+detailIndent))
+				separator = "\n"
+			}
+
+			return
+		}
+
+		layer, ok := current.(*wrapper) //nolint:errorlint // a particular layer is inspected
+		if !ok {
+			if errors.Unwrap(current) == nil {
+				_, _ = io.WriteString(w, separator+RedactText(current.Error()))
+			}
+
+			continue
+		}
+
+		_, _ = io.WriteString(w, separator+RedactText(layer.msg))
+		separator = "\n"
+
+		for _, detail := range layer.ownDetails() {
+			writeDetail(w, detail)
+		}
+
+		if len(layer.stack) != 0 {
+			trace := strings.ReplaceAll(fmt.Sprintf("%+v", layer.stack), "\n", "\n"+fieldIndent)
+			fmt.Fprintf(w, "\n%sstack:\n%s%s", detailIndent, fieldIndent, trace)
+		}
+	}
+}
+
+// writeDetail writes the detail's non-empty fields as an indented list item.
+// The detail is redacted by the package Redactor.
+func writeDetail(w io.Writer, detail Detail) {
+	detail = Redact(detail)
+
+	fields := [...]struct {
+		name  string
+		value string
+	}{
+		{name: "domain", value: detail.domain},
+		{name: "code", value: detail.code},
+		{name: "field", value: detail.field},
+		{name: "reason", value: detail.Reason()},
+		{name: "description", value: detail.Description()},
+		{name: "help_url", value: detail.helpURL},
+		{name: "severity", value: detail.severity.String()},
+		{name: "visibility", value: visibilityName(detail.visibility)},
+	}
+
+	prefix := "\n" + detailIndent + "- "
+
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s%s: %s", prefix, f.name, f.value)
+		prefix = "\n" + fieldIndent
+	}
+
+	if len(detail.meta) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%smeta:", prefix)
+
+	keys := make([]string, 0, len(detail.meta))
+	for key := range detail.meta {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.ReplaceAll(fmt.Sprintf("%v", detail.meta[key]), "\n", "\n"+metaIndent)
+		fmt.Fprintf(w, "\n%s%s: %s", metaIndent, key, value)
+	}
+}
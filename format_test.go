@@ -0,0 +1,119 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	inner := NewNotFound(
+		"user not found",
+		NewDetail(
+			WithDomain("user.management"),
+			WithCode("user_not_found"),
+			WithField("user.id"),
+			WithReason("no user with the provided id"),
+			WithDescription("user lookup failed"),
+			WithMeta(Meta{
+				"id":      42,
+				"attempt": "first",
+			}),
+		),
+	)
+
+	outer := Wrap(
+		fmt.Errorf("repository: %w", inner),
+		"get order",
+		NewDetail(WithCode("order_not_found")),
+		NewDetail(WithDomain("order.management"), WithField("order.user"),
+			WithSeverity(SeverityWarning), WithVisibility(VisibilityInternal)),
+	)
+
+	tests := map[string]struct {
+		err    error
+		format string
+		want   string
+	}{
+		"string": {
+			err:    outer,
+			format: "%s",
+			want:   "get order: repository: user not found: not found",
+		},
+		"value": {
+			err:    outer,
+			format: "%v",
+			want:   "get order: repository: user not found: not found",
+		},
+		"quoted": {
+			err:    New(`dummy "message"`),
+			format: "%q",
+			want:   `"dummy \"message\""`,
+		},
+		"unsupported_verb": {
+			err:    New("dummy message"),
+			format: "%d",
+			want:   "%!d(*errdetail.wrapper=dummy message)",
+		},
+		"detailed/new": {
+			err:    New("dummy message", NewDetail(WithCode("dummy_code"))),
+			format: "%+v",
+			want: "dummy message\n" +
+				"    - code: dummy_code",
+		},
+		"detailed/no_details": {
+			err:    NewInternal("dummy message"),
+			format: "%+v",
+			want: "dummy message: internal\n" +
+				"internal",
+		},
+		"detailed/chain": {
+			err:    outer,
+			format: "%+v",
+			want: "get order: repository: user not found: not found\n" +
+				"    - code: order_not_found\n" +
+				"    - domain: order.management\n" +
+				"      field: order.user\n" +
+				"      severity: warning\n" +
+				"      visibility: internal\n" +
+				"user not found: not found\n" +
+				"    - domain: user.management\n" +
+				"      code: user_not_found\n" +
+				"      field: user.id\n" +
+				"      reason: no user with the provided id\n" +
+				"      description: user lookup failed\n" +
+				"      meta:\n" +
+				"        attempt: first\n" +
+				"        id: 42\n" +
+				"not found",
+		},
+		"go_syntax": {
+			err:    Wrap(ErrNotFound, "dummy message", NewDetail(WithCode("dummy_code"))),
+			format: "%#v",
+			want: `&errdetail.wrapper{msg:"dummy message: not found", underlying:"not found", ` +
+				`details:[]errdetail.Detail{errdetail.Detail{field:"", description:"", descriptionTemplate:"", ` +
+				`code:"dummy_code", domain:"", reason:"", reasonTemplate:"", templateArgs:errdetail.Meta(nil), ` +
+				`helpURL:"", messageID:"", messageArgs:errdetail.Meta(nil), ` +
+				`meta:errdetail.Meta(nil), payload:interface {}(nil), visibility:0x0, severity:0x0, redacted:false, filled:true}}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.err))
+		})
+	}
+}
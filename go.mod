@@ -1,5 +1,15 @@
 module github.com/dnozdrin/errdetail
 
-go 1.13
+go 1.18
 
-require github.com/stretchr/testify v1.8.1
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/stretchr/testify v1.8.1
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
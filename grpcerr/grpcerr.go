@@ -0,0 +1,462 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package grpcerr converts detailed errors into gRPC statuses with
+// google.rpc error details and converts received statuses back into errors.
+package grpcerr
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/dnozdrin/errdetail"
+)
+
+const (
+	// KindDomain is the domain of the google.rpc.ErrorInfo that holds the
+	// code of the error's kind. It allows to restore kinds that share the same
+	// gRPC code, such as ErrNotFound and ErrRemoved, as well as custom kinds.
+	KindDomain = "errdetail.kind"
+
+	// Keys of the google.rpc.ErrorInfo metadata that hold the detail's
+	// attributes other than Meta entries.
+	descriptionKey = "description"
+	reasonKey      = "reason"
+	fieldKey       = "field"
+	helpURLKey     = "help_url"
+	severityKey    = "severity"
+	visibilityKey  = "visibility"
+	// jsonKeysKey holds the JSON array of the keys of JSON encoded Meta values.
+	jsonKeysKey = "json_keys"
+)
+
+// Option is a function type for the settings' setters of Status and
+// the server interceptors.
+type Option func(*options)
+
+type options struct {
+	// internals disables conversion of the public view of errors.
+	internals bool
+}
+
+// WithInternals is an option for Status and the server interceptors that
+// disables conversion of the public view of errors, so the full error messages
+// and internal details are sent. It is meant for calls between trusted services
+// and development environments only. By default, statuses are built from
+// errdetail.PublicView.
+func WithInternals() Option {
+	return func(o *options) {
+		o.internals = true
+	}
+}
+
+// CodeOf returns the gRPC code of the kind. Custom kinds are mapped by their
+// nearest predefined ancestor. ErrRemoved is mapped to codes.NotFound and
+// ErrDataCorrupted is mapped to codes.DataLoss. The zero Kind and custom
+// kinds without predefined ancestors are mapped to codes.Unknown.
+func CodeOf(kind errdetail.Kind) codes.Code {
+	code, ok := kindCodes()[kind.Resolve(func(k errdetail.Kind) bool {
+		_, ok := kindCodes()[k]
+
+		return ok
+	})]
+	if !ok {
+		return codes.Unknown
+	}
+
+	return code
+}
+
+// KindOfCode returns the kind of the gRPC code. Both codes.OK and codes.Unknown
+// correspond to the zero Kind.
+func KindOfCode(code codes.Code) errdetail.Kind {
+	switch code {
+	case codes.OK, codes.Unknown:
+		return ""
+	case codes.NotFound:
+		return errdetail.ErrNotFound
+	}
+
+	for kind, c := range kindCodes() {
+		if c == code {
+			return kind
+		}
+	}
+
+	return ""
+}
+
+// kindCodes returns the gRPC codes of the predefined kinds. The catalogdoc
+// package mirrors the mapping by catalogdoc.GRPCCode.
+func kindCodes() map[errdetail.Kind]codes.Code {
+	return map[errdetail.Kind]codes.Code{
+		errdetail.ErrInvalidArgument:    codes.InvalidArgument,
+		errdetail.ErrFailedPrecondition: codes.FailedPrecondition,
+		errdetail.ErrOutOfRange:         codes.OutOfRange,
+		errdetail.ErrUnauthenticated:    codes.Unauthenticated,
+		errdetail.ErrPermissionDenied:   codes.PermissionDenied,
+		errdetail.ErrNotFound:           codes.NotFound,
+		errdetail.ErrAborted:            codes.Aborted,
+		errdetail.ErrAlreadyExists:      codes.AlreadyExists,
+		errdetail.ErrRemoved:            codes.NotFound,
+		errdetail.ErrResourceExhausted:  codes.ResourceExhausted,
+		errdetail.ErrDataCorrupted:      codes.DataLoss,
+		errdetail.ErrInternal:           codes.Internal,
+		errdetail.ErrNotImplemented:     codes.Unimplemented,
+		errdetail.ErrUnavailable:        codes.Unavailable,
+		errdetail.ErrDeadlineExceeded:   codes.DeadlineExceeded,
+		errdetail.ErrCancelled:          codes.Canceled,
+	}
+}
+
+// Status converts the error into a gRPC status. The status code is determined
+// by the error's kind, the status message is the error message. The kind's
+// code is put into a google.rpc.ErrorInfo of KindDomain.
+//
+// Details that have a field are converted into google.rpc.BadRequest field
+// violations, which hold the detail's field, code and reason. Other details
+// are converted into google.rpc.ErrorInfo, which holds the detail's code,
+// domain and Meta, as well as the detail's description, reason, help URL,
+// severity and visibility in the metadata under the "description", "reason",
+// "help_url", "severity" and "visibility" keys. A detail with a field that
+// carries more than the field, the code and the reason is converted into
+// a google.rpc.ErrorInfo as well, which refers to the violation by the "field"
+// metadata key. Meta values other than strings are encoded to JSON, their keys
+// are listed under the "json_keys" key. Meta entries with any of these keys
+// are not transferred. The details are redacted by errdetail.RedactDetails,
+// and the status message by errdetail.RedactText.
+//
+// Errors without a kind that carry a gRPC status themselves, such as errors
+// returned by gRPC clients, are converted into their own status. Unless
+// WithInternals is set, the public view of other errors is converted, see
+// errdetail.PublicView.
+// Status returns the OK status for the nil error.
+func Status(err error, opts ...Option) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	kind := errdetail.KindOf(err)
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if kind == "" && errors.As(err, &grpcErr) {
+		return grpcErr.GRPCStatus()
+	}
+
+	var settings options
+	for i := range opts {
+		opts[i](&settings)
+	}
+
+	if !settings.internals {
+		err = errdetail.PublicView(err)
+	}
+
+	st := status.New(CodeOf(kind), errdetail.RedactText(err.Error()))
+
+	messages := statusDetails(kind, errdetail.RedactDetails(errdetail.ExtractDetails(err)))
+	if len(messages) == 0 {
+		return st
+	}
+
+	withDetails, detailsErr := st.WithDetails(messages...)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// statusDetails converts the kind and the details into status details,
+// keeping the details' order. Consecutive details with a field share
+// the same google.rpc.BadRequest.
+func statusDetails(kind errdetail.Kind, details []errdetail.Detail) []protoadapt.MessageV1 {
+	var messages []protoadapt.MessageV1
+
+	if kind != "" {
+		messages = append(messages, &errdetails.ErrorInfo{Reason: kind.Code(), Domain: KindDomain})
+	}
+
+	var (
+		badRequest *errdetails.BadRequest
+		fieldInfos []protoadapt.MessageV1
+	)
+
+	for i := range details {
+		if details[i].Field() == "" {
+			messages = append(messages, fieldInfos...)
+			badRequest, fieldInfos = nil, nil
+
+			messages = append(messages, errorInfo(details[i]))
+
+			continue
+		}
+
+		if badRequest == nil {
+			badRequest = &errdetails.BadRequest{}
+			messages = append(messages, badRequest)
+		}
+
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       details[i].Field(),
+			Description: details[i].Reason(),
+			Reason:      details[i].Code(),
+		})
+
+		if hasAttributes(details[i]) {
+			info := errorInfo(details[i])
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string, 1)
+			}
+
+			info.Metadata[fieldKey] = details[i].Field()
+			fieldInfos = append(fieldInfos, info)
+		}
+	}
+
+	return append(messages, fieldInfos...)
+}
+
+// hasAttributes reports whether the detail carries attributes that do not
+// fit into a google.rpc.BadRequest field violation.
+func hasAttributes(detail errdetail.Detail) bool {
+	return detail.Domain() != "" || detail.Description() != "" || len(detail.Meta()) != 0 ||
+		detail.HelpURL() != "" || detail.Severity() != 0 || detail.Visibility() != errdetail.VisibilityPublic
+}
+
+func errorInfo(detail errdetail.Detail) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason: detail.Code(),
+		Domain: detail.Domain(),
+	}
+
+	meta := detail.Meta()
+	metadata := make(map[string]string, len(meta)+2)
+
+	var jsonKeys []string
+
+	for key, value := range meta {
+		if isReservedKey(key) {
+			continue
+		}
+
+		if s, ok := value.(string); ok {
+			metadata[key] = s
+
+			continue
+		}
+
+		if encoded, err := json.Marshal(value); err == nil {
+			metadata[key] = string(encoded)
+			jsonKeys = append(jsonKeys, key)
+		}
+	}
+
+	if len(jsonKeys) != 0 {
+		sort.Strings(jsonKeys)
+
+		encoded, _ := json.Marshal(jsonKeys) //nolint:errchkjson // a slice of strings is always encoded
+		metadata[jsonKeysKey] = string(encoded)
+	}
+
+	attributes := map[string]string{
+		descriptionKey: detail.Description(),
+		reasonKey:      detail.Reason(),
+		helpURLKey:     detail.HelpURL(),
+		severityKey:    detail.Severity().String(),
+	}
+
+	if detail.Visibility() != errdetail.VisibilityPublic {
+		attributes[visibilityKey] = detail.Visibility().String()
+	}
+
+	for key, value := range attributes {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+
+	if len(metadata) != 0 {
+		info.Metadata = metadata
+	}
+
+	return info
+}
+
+// Error converts the gRPC status back into an error. The error's kind is
+// taken from the google.rpc.ErrorInfo of KindDomain, or determined by the
+// status code if there is no such ErrorInfo. The error's details are restored
+// from google.rpc.ErrorInfo and google.rpc.BadRequest status details, other
+// status details are ignored. A google.rpc.ErrorInfo that refers to a field
+// violation restores the violation's detail. JSON encoded Meta values are
+// decoded, the other Meta values are strings.
+// Error returns nil for the nil and the OK statuses.
+func Error(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	kind := KindOfCode(st.Code())
+
+	var (
+		details    []errdetail.Detail
+		violations []int // indexes of the details restored from field violations
+	)
+
+	for _, message := range st.Details() {
+		switch message := message.(type) {
+		case *errdetails.ErrorInfo:
+			if message.GetDomain() == KindDomain {
+				kind = kindOf(message.GetReason(), kind)
+
+				continue
+			}
+
+			if i, ok := violationOf(details, violations, message); ok {
+				details[violations[i]] = infoDetail(message)
+				violations = append(violations[:i], violations[i+1:]...)
+
+				continue
+			}
+
+			details = append(details, infoDetail(message))
+		case *errdetails.BadRequest:
+			for _, violation := range message.GetFieldViolations() {
+				violations = append(violations, len(details))
+				details = append(details, errdetail.NewDetail(
+					errdetail.WithCode(violation.GetReason()),
+					errdetail.WithField(violation.GetField()),
+					errdetail.WithReason(violation.GetDescription()),
+				))
+			}
+		}
+	}
+
+	return errdetail.Restore(st.Message(), kind, details...)
+}
+
+// violationOf returns the position in violations of the detail restored from
+// the field violation the google.rpc.ErrorInfo refers to, if any.
+func violationOf(details []errdetail.Detail, violations []int, info *errdetails.ErrorInfo) (int, bool) {
+	field, ok := info.GetMetadata()[fieldKey]
+	if !ok {
+		return 0, false
+	}
+
+	for i, index := range violations {
+		if details[index].Field() == field && details[index].Code() == info.GetReason() {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// kindOf returns the kind with the given code, or the fallback kind if
+// no such kind is known.
+func kindOf(code string, fallback errdetail.Kind) errdetail.Kind {
+	for _, kind := range errdetail.Kinds() {
+		if kind.Code() == code {
+			return kind
+		}
+	}
+
+	return fallback
+}
+
+// infoDetail converts the google.rpc.ErrorInfo back into a detail.
+func infoDetail(info *errdetails.ErrorInfo) errdetail.Detail {
+	metadata := info.GetMetadata()
+
+	var jsonKeys []string
+	if encoded, ok := metadata[jsonKeysKey]; ok {
+		_ = json.Unmarshal([]byte(encoded), &jsonKeys)
+	}
+
+	var meta errdetail.Meta
+
+	for key, value := range metadata {
+		if isReservedKey(key) {
+			continue
+		}
+
+		if meta == nil {
+			meta = make(errdetail.Meta, len(metadata))
+		}
+
+		meta[key] = metaValue(value, contains(jsonKeys, key))
+	}
+
+	return errdetail.NewDetail(
+		errdetail.WithDomain(info.GetDomain()),
+		errdetail.WithCode(info.GetReason()),
+		errdetail.WithDescription(metadata[descriptionKey]),
+		errdetail.WithField(metadata[fieldKey]),
+		errdetail.WithReason(metadata[reasonKey]),
+		errdetail.WithHelpURL(metadata[helpURLKey]),
+		errdetail.WithMeta(meta),
+		errdetail.WithSeverity(severityOf(metadata[severityKey])),
+		errdetail.WithVisibility(visibilityOf(metadata[visibilityKey])),
+	)
+}
+
+// metaValue returns the Meta value decoded from JSON if it is encoded,
+// or the value itself otherwise.
+func metaValue(value string, encoded bool) interface{} {
+	if !encoded {
+		return value
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+func isReservedKey(key string) bool {
+	switch key {
+	case descriptionKey, reasonKey, fieldKey, helpURLKey, severityKey, visibilityKey, jsonKeysKey:
+		return true
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severityOf returns the severity with the given name, or the unspecified one.
+func severityOf(name string) errdetail.Severity {
+	for _, severity := range errdetail.Severities() {
+		if severity.String() == name {
+			return severity
+		}
+	}
+
+	return 0
+}
+
+// visibilityOf returns the visibility with the given name, or the public one.
+func visibilityOf(name string) errdetail.Visibility {
+	if name == errdetail.VisibilityInternal.String() {
+		return errdetail.VisibilityInternal
+	}
+
+	return errdetail.VisibilityPublic
+}
@@ -0,0 +1,409 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package grpcerr_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/catalogdoc"
+
+	. "github.com/dnozdrin/errdetail/grpcerr"
+)
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var (
+	errQuotaExceeded = errdetail.DefineKind("grpc quota exceeded", errdetail.ErrResourceExhausted)
+	errOrphan        = errdetail.DefineKind("grpc orphan", "")
+)
+
+func TestCodeOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		kind errdetail.Kind
+		want codes.Code
+	}{
+		"invalid_argument": {kind: errdetail.ErrInvalidArgument, want: codes.InvalidArgument},
+		"not_found":        {kind: errdetail.ErrNotFound, want: codes.NotFound},
+		"removed":          {kind: errdetail.ErrRemoved, want: codes.NotFound},
+		"data_corrupted":   {kind: errdetail.ErrDataCorrupted, want: codes.DataLoss},
+		"not_implemented":  {kind: errdetail.ErrNotImplemented, want: codes.Unimplemented},
+		"cancelled":        {kind: errdetail.ErrCancelled, want: codes.Canceled},
+		"custom":           {kind: errQuotaExceeded, want: codes.ResourceExhausted},
+		"orphan":           {kind: errOrphan, want: codes.Unknown},
+		"unknown":          {kind: "", want: codes.Unknown},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, CodeOf(tt.kind))
+		})
+	}
+}
+
+// TestCodeOf_catalogdoc checks that the catalog documentation reports
+// the same gRPC codes the kinds are mapped to.
+func TestCodeOf_catalogdoc(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range append(errdetail.Kinds(), errQuotaExceeded, errOrphan, "") {
+		assert.Equal(t, code.Code(CodeOf(kind)).String(), catalogdoc.GRPCCode(kind), kind)
+	}
+}
+
+func TestKindOfCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		code codes.Code
+		want errdetail.Kind
+	}{
+		"invalid_argument": {code: codes.InvalidArgument, want: errdetail.ErrInvalidArgument},
+		"not_found":        {code: codes.NotFound, want: errdetail.ErrNotFound},
+		"data_loss":        {code: codes.DataLoss, want: errdetail.ErrDataCorrupted},
+		"unimplemented":    {code: codes.Unimplemented, want: errdetail.ErrNotImplemented},
+		"canceled":         {code: codes.Canceled, want: errdetail.ErrCancelled},
+		"unknown":          {code: codes.Unknown, want: ""},
+		"ok":               {code: codes.OK, want: ""},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, KindOfCode(tt.code))
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err     error
+		opts    []Option
+		code    codes.Code
+		message string
+		details []proto.Message
+	}{
+		"no_error": {
+			err:  nil,
+			code: codes.OK,
+		},
+		"details": {
+			err: errdetail.NewInvalidArgument(
+				"bad request",
+				errdetail.NewDetail(
+					errdetail.WithDomain("user.management"),
+					errdetail.WithCode("USER_BLOCKED"),
+					errdetail.WithDescription("user is blocked"),
+					errdetail.WithMeta(errdetail.Meta{"user": "42", "attempts": 3}),
+				),
+				errdetail.NewDetail(
+					errdetail.WithCode("INVALID_EMAIL"),
+					errdetail.WithField("user.email"),
+					errdetail.WithReason("an email must contain @"),
+				),
+				errdetail.NewDetail(errdetail.WithField("user.name")),
+			),
+			code:    codes.InvalidArgument,
+			message: "bad request: invalid argument",
+			details: []proto.Message{
+				&errdetails.ErrorInfo{Reason: "INVALID_ARGUMENT", Domain: KindDomain},
+				&errdetails.ErrorInfo{
+					Reason: "USER_BLOCKED",
+					Domain: "user.management",
+					Metadata: map[string]string{
+						"user":        "42",
+						"attempts":    "3",
+						"json_keys":   `["attempts"]`,
+						"description": "user is blocked",
+					},
+				},
+				&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "user.email", Reason: "INVALID_EMAIL", Description: "an email must contain @"},
+					{Field: "user.name"},
+				}},
+			},
+		},
+		"field_attributes": {
+			err: errdetail.NewInvalidArgument(
+				"bad request",
+				errdetail.NewDetail(
+					errdetail.WithCode("INVALID_EMAIL"),
+					errdetail.WithField("user.email"),
+					errdetail.WithHelpURL("https://example.com/errors#invalid-email"),
+				),
+				errdetail.NewDetail(errdetail.WithField("user.name")),
+			),
+			code:    codes.InvalidArgument,
+			message: "bad request: invalid argument",
+			details: []proto.Message{
+				&errdetails.ErrorInfo{Reason: "INVALID_ARGUMENT", Domain: KindDomain},
+				&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "user.email", Reason: "INVALID_EMAIL"},
+					{Field: "user.name"},
+				}},
+				&errdetails.ErrorInfo{
+					Reason:   "INVALID_EMAIL",
+					Metadata: map[string]string{"field": "user.email", "help_url": "https://example.com/errors#invalid-email"},
+				},
+			},
+		},
+		"unknown": {
+			err:     assert.AnError,
+			code:    codes.Unknown,
+			message: "",
+		},
+		"unknown_internals": {
+			err:     assert.AnError,
+			opts:    []Option{WithInternals()},
+			code:    codes.Unknown,
+			message: assert.AnError.Error(),
+		},
+		"context": {
+			err:     fmt.Errorf("call: %w", context.DeadlineExceeded),
+			code:    codes.DeadlineExceeded,
+			message: "deadline exceeded",
+			details: []proto.Message{
+				&errdetails.ErrorInfo{Reason: "DEADLINE_EXCEEDED", Domain: KindDomain},
+			},
+		},
+		"context_internals": {
+			err:     fmt.Errorf("call: %w", context.DeadlineExceeded),
+			opts:    []Option{WithInternals()},
+			code:    codes.DeadlineExceeded,
+			message: "call: context deadline exceeded",
+			details: []proto.Message{
+				&errdetails.ErrorInfo{Reason: "DEADLINE_EXCEEDED", Domain: KindDomain},
+			},
+		},
+		"internal_details": {
+			err: errdetail.Wrap(errdetail.Mark(errdetail.NewNotFound("query shard 3",
+				errdetail.NewDetail(errdetail.WithCode("SHARD_DOWN"), errdetail.WithVisibility(errdetail.VisibilityInternal)),
+			), errdetail.VisibilityInternal), "user not found", errdetail.NewDetail(errdetail.WithCode("USER_NOT_FOUND"))),
+			code:    codes.NotFound,
+			message: "user not found: not found",
+			details: []proto.Message{
+				&errdetails.ErrorInfo{Reason: "NOT_FOUND", Domain: KindDomain},
+				&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND"},
+			},
+		},
+		"status_error": {
+			err:     fmt.Errorf("call: %w", status.Error(codes.Unauthenticated, "token expired")),
+			code:    codes.Unauthenticated,
+			message: "token expired",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			st := Status(tt.err, tt.opts...)
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+			require.Len(t, st.Details(), len(tt.details))
+
+			for i := range tt.details {
+				got, ok := st.Details()[i].(proto.Message)
+				require.True(t, ok)
+				assert.True(t, proto.Equal(tt.details[i], got), "%v != %v", tt.details[i], got)
+			}
+		})
+	}
+}
+
+// TestStatus_redacted is not parallel, since it sets the package Redactor.
+func TestStatus_redacted(t *testing.T) {
+	errdetail.SetRedactor(errdetail.NewRedactor(
+		errdetail.RedactKeys(errdetail.RedactDrop, "*token*"),
+		errdetail.RedactValues(errdetail.RedactMask, errdetail.MatchEmail()),
+	))
+	t.Cleanup(func() { errdetail.SetRedactor(nil) })
+
+	st := Status(errdetail.NewAlreadyExists("user jane.doe@example.com exists",
+		errdetail.NewDetail(errdetail.WithCode("email_taken"), errdetail.WithReason("jane.doe@example.com is taken")),
+		errdetail.NewDetail(errdetail.WithField("user.email"), errdetail.WithReason("jane.doe@example.com is taken")),
+		errdetail.NewDetail(errdetail.WithCode("session"), errdetail.WithMeta(errdetail.Meta{"api_token": "s3cr3t-t0k3n"})),
+	))
+
+	encoded := fmt.Sprint(st.Proto())
+	assert.Contains(t, encoded, "[REDACTED]")
+	assert.NotContains(t, encoded, "jane.doe@example.com")
+	assert.NotContains(t, encoded, "s3cr3t-t0k3n")
+	assert.Equal(t, "user [REDACTED] exists: already exists", st.Message())
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	details := []errdetail.Detail{
+		errdetail.NewDetail(
+			errdetail.WithCode("INVALID_EMAIL"),
+			errdetail.WithField("user.email"),
+			errdetail.WithReason("an email must contain @"),
+		),
+		errdetail.NewDetail(
+			errdetail.WithDomain("user.management"),
+			errdetail.WithCode("USER_BLOCKED"),
+			errdetail.WithDescription("user is blocked"),
+			errdetail.WithReason("too many attempts"),
+			errdetail.WithMeta(errdetail.Meta{"user": "42"}),
+		),
+		errdetail.NewDetail(errdetail.WithField("user.name")),
+	}
+
+	tests := map[string]struct {
+		err  error
+		kind errdetail.Kind
+	}{
+		"predefined": {
+			err:  errdetail.NewInvalidArgument("bad request", details...),
+			kind: errdetail.ErrInvalidArgument,
+		},
+		"shared_code": {
+			err:  errdetail.NewRemoved("user removed", details...),
+			kind: errdetail.ErrRemoved,
+		},
+		"custom": {
+			err:  errdetail.Wrap(errQuotaExceeded, "daily limit", details...),
+			kind: errQuotaExceeded,
+		},
+		"no_details": {
+			err:  errdetail.NewDataCorrupted("checksum mismatch"),
+			kind: errdetail.ErrDataCorrupted,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := proto.Marshal(Status(tt.err).Proto())
+			require.NoError(t, err)
+
+			received := status.New(codes.OK, "").Proto()
+			require.NoError(t, proto.Unmarshal(encoded, received))
+
+			restored := Error(status.FromProto(received))
+
+			assert.Equal(t, tt.kind, errdetail.KindOf(restored))
+			assert.ErrorIs(t, restored, tt.kind)
+			assert.Equal(t, tt.err.Error(), restored.Error())
+			assert.Equal(t, errdetail.ExtractDetails(tt.err), errdetail.ExtractDetails(restored))
+		})
+	}
+
+	t.Run("full_details", func(t *testing.T) {
+		t.Parallel()
+
+		options := []errdetail.Option{
+			errdetail.WithDomain("user.management"),
+			errdetail.WithCode("INVALID_EMAIL"),
+			errdetail.WithDescription("invalid email"),
+			errdetail.WithReason("an email must contain @"),
+			errdetail.WithHelpURL("https://example.com/errors#invalid-email"),
+			errdetail.WithSeverity(errdetail.SeverityWarning),
+			errdetail.WithVisibility(errdetail.VisibilityInternal),
+		}
+		field := errdetail.WithField("user.email")
+		required := errdetail.NewDetail(errdetail.WithCode("REQUIRED"), errdetail.WithField("user.name"))
+		fieldMeta := errdetail.WithMeta(errdetail.Meta{"pattern": "rfc5322", "attempt": 2})
+		infoMeta := errdetail.WithMeta(errdetail.Meta{"ids": []int{4, 2}})
+		err := errdetail.NewInvalidArgument("bad request",
+			errdetail.NewDetail(append(options, field, fieldMeta)...),
+			required,
+			errdetail.NewDetail(append(options, infoMeta)...),
+		)
+
+		encoded, marshalErr := proto.Marshal(Status(err, WithInternals()).Proto())
+		require.NoError(t, marshalErr)
+
+		received := status.New(codes.OK, "").Proto()
+		require.NoError(t, proto.Unmarshal(encoded, received))
+
+		// JSON encoded Meta values are decoded the same way as by encoding/json.
+		fieldMeta = errdetail.WithMeta(errdetail.Meta{"pattern": "rfc5322", "attempt": float64(2)})
+		infoMeta = errdetail.WithMeta(errdetail.Meta{"ids": []interface{}{float64(4), float64(2)}})
+
+		assert.Equal(t, []errdetail.Detail{
+			errdetail.NewDetail(append(options, field, fieldMeta)...),
+			required,
+			errdetail.NewDetail(append(options, infoMeta)...),
+		}, errdetail.ExtractDetails(Error(status.FromProto(received))))
+	})
+
+	t.Run("reserved_meta_keys", func(t *testing.T) {
+		t.Parallel()
+
+		err := errdetail.NewInvalidArgument("bad request", errdetail.NewDetail(
+			errdetail.WithCode("INVALID_EMAIL"),
+			errdetail.WithMeta(errdetail.Meta{
+				"field":       "user.email",
+				"reason":      "spoof",
+				"description": "d",
+				"help_url":    "https://example.com",
+				"severity":    "critical",
+				"visibility":  "internal",
+				"json_keys":   `["pattern"]`,
+				"pattern":     "rfc5322",
+			}),
+		))
+
+		assert.Equal(t, []errdetail.Detail{errdetail.NewDetail(
+			errdetail.WithCode("INVALID_EMAIL"),
+			errdetail.WithMeta(errdetail.Meta{"pattern": "rfc5322"}),
+		)}, errdetail.ExtractDetails(Error(Status(err))))
+	})
+
+	t.Run("foreign_status", func(t *testing.T) {
+		t.Parallel()
+
+		st, err := status.New(codes.NotFound, "user not found").WithDetails(
+			&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND", Domain: "users.example.com"},
+			&errdetails.RetryInfo{},
+		)
+		require.NoError(t, err)
+
+		restored := Error(st)
+
+		assert.ErrorIs(t, restored, errdetail.ErrNotFound)
+		assert.EqualError(t, restored, "user not found")
+		assert.Equal(t, []errdetail.Detail{errdetail.NewDetail(
+			errdetail.WithDomain("users.example.com"),
+			errdetail.WithCode("USER_NOT_FOUND"),
+		)}, errdetail.ExtractDetails(restored))
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, Error(status.New(codes.OK, "")))
+		assert.NoError(t, Error(nil))
+	})
+}
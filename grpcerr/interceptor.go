@@ -0,0 +1,152 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a server interceptor that converts errors
+// returned by unary handlers into statuses by Status with the options.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		return resp, toStatusError(err, opts)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that converts errors
+// returned by streaming handlers into statuses by Status with the options.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toStatusError(handler(srv, ss), opts)
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor that converts statuses
+// received from unary calls into errors by Error. The errors keep the received
+// statuses, so status.Code and status.FromError report them.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return fromStatusError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that converts statuses
+// received from streaming calls into errors by Error, the same way as
+// UnaryClientInterceptor does. The io.EOF error, that marks the end of
+// a stream, is returned as is.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromStatusError(err)
+		}
+
+		return &clientStream{ClientStream: stream}, nil
+	}
+}
+
+// clientStream converts errors of the underlying stream's operations.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return fromStatusError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return fromStatusError(s.ClientStream.CloseSend())
+}
+
+// toStatusError converts the error into a status error by Status.
+func toStatusError(err error, opts []Option) error {
+	if err == nil {
+		return nil
+	}
+
+	return Status(err, opts...).Err()
+}
+
+func fromStatusError(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return &statusError{err: Error(st), st: st}
+}
+
+// statusError is an error converted from a received status. It keeps the
+// status for status.Code and status.FromError, while the converted error
+// is unwrapped by errors.Is, errors.As and the errdetail functions.
+type statusError struct {
+	err error
+	st  *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the received status.
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+// Format formats the converted error, so its details are printed by %+v.
+func (e *statusError) Format(s fmt.State, verb rune) {
+	if formatter, ok := e.err.(fmt.Formatter); ok {
+		formatter.Format(s, verb)
+
+		return
+	}
+
+	_, _ = io.WriteString(s, e.Error())
+}
+
+// LogValue logs the converted error the same way as detailed errors are logged.
+func (e *statusError) LogValue() slog.Value {
+	if valuer, ok := e.err.(slog.LogValuer); ok {
+		return valuer.LogValue()
+	}
+
+	return slog.StringValue(e.Error())
+}
+
+// MarshalJSON encodes the converted error the same way as detailed errors are encoded.
+func (e *statusError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.err)
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package grpcerr_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dnozdrin/errdetail"
+
+	. "github.com/dnozdrin/errdetail/grpcerr"
+)
+
+const bufSize = 1024 * 1024
+
+// healthServer returns the same error from every call. Watch sends a single
+// response before the error.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	err error
+}
+
+func (s *healthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+	if err != nil {
+		return err
+	}
+
+	return s.err
+}
+
+// dial starts a server with the interceptors options that returns the error
+// over an in-memory connection and returns a client connected to it.
+func dial(t *testing.T, err error, serverOpts []Option, clientOpts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverOpts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverOpts...)),
+	)
+	healthpb.RegisterHealthServer(server, &healthServer{err: err})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	opts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, clientOpts...)
+
+	conn, dialErr := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, dialErr)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	details := []errdetail.Detail{
+		errdetail.NewDetail(
+			errdetail.WithDomain("health"),
+			errdetail.WithCode("DATABASE_DOWN"),
+			errdetail.WithMeta(errdetail.Meta{"replica": "primary"}),
+		),
+		errdetail.NewDetail(errdetail.WithCode("REQUIRED"), errdetail.WithField("service")),
+	}
+
+	tests := map[string]struct {
+		err        error
+		serverOpts []Option
+		kind       errdetail.Kind
+		message    string
+		details    []errdetail.Detail
+	}{
+		"no_error": {
+			err: nil,
+		},
+		"detailed": {
+			err:     errdetail.NewUnavailable("database is down", details...),
+			kind:    errdetail.ErrUnavailable,
+			message: "database is down: unavailable",
+			details: details,
+		},
+		"custom_kind": {
+			err:     errdetail.Wrap(errQuotaExceeded, "too many checks"),
+			kind:    errQuotaExceeded,
+			message: "too many checks: grpc quota exceeded",
+		},
+		"status": {
+			err:     status.Error(codes.PermissionDenied, "access denied"),
+			kind:    errdetail.ErrPermissionDenied,
+			message: "access denied",
+		},
+		"plain": {
+			err:     errdetail.Wrap(errors.New("dial tcp 10.0.0.3:5432"), "check failed"),
+			kind:    "",
+			message: "check failed",
+		},
+		"internal": {
+			err: errdetail.NewUnavailable("database is down", append(details, errdetail.NewDetail(
+				errdetail.WithCode("REPLICA_LAG"),
+				errdetail.WithVisibility(errdetail.VisibilityInternal),
+			))...),
+			kind:    errdetail.ErrUnavailable,
+			message: "database is down: unavailable",
+			details: details,
+		},
+		"internals": {
+			err:        errdetail.Wrap(errors.New("dial tcp 10.0.0.3:5432"), "check failed"),
+			serverOpts: []Option{WithInternals()},
+			kind:       "",
+			message:    "check failed: dial tcp 10.0.0.3:5432",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := dial(t, tt.err, tt.serverOpts,
+				grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+				grpc.WithStreamInterceptor(StreamClientInterceptor()),
+			)
+
+			t.Run("unary", func(t *testing.T) {
+				_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+				assertError(t, err, tt.kind, tt.message, tt.details)
+			})
+
+			t.Run("stream", func(t *testing.T) {
+				stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+				require.NoError(t, err)
+
+				_, err = stream.Recv()
+				require.NoError(t, err)
+
+				_, err = stream.Recv()
+				if tt.err == nil {
+					assert.ErrorIs(t, err, io.EOF)
+
+					return
+				}
+
+				assertError(t, err, tt.kind, tt.message, tt.details)
+			})
+		})
+	}
+
+	t.Run("server_only", func(t *testing.T) {
+		t.Parallel()
+
+		client := dial(t, errdetail.NewNotFound("service not found", details...), nil)
+
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Len(t, st.Details(), 3)
+		assert.False(t, errors.Is(err, errdetail.ErrNotFound))
+	})
+}
+
+func assertError(t *testing.T, err error, kind errdetail.Kind, message string, details []errdetail.Detail) {
+	t.Helper()
+
+	if message == "" {
+		assert.NoError(t, err)
+
+		return
+	}
+
+	assert.EqualError(t, err, message)
+	assert.Equal(t, CodeOf(kind), status.Code(err))
+	assert.Equal(t, kind, errdetail.KindOf(err))
+	assert.Equal(t, details, errdetail.ExtractDetails(err))
+
+	if kind != "" {
+		assert.ErrorIs(t, err, kind)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package httperr renders detailed errors as HTTP responses.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dnozdrin/errdetail"
+)
+
+// ErrorResponse represents an HTTP server response in case of an error.
+type ErrorResponse struct {
+	Error *Error `json:"error,omitempty"`
+}
+
+// Error is expected to be filled only via NewErrorResponse.
+// Intentionally omits wrapped error's message presentation.
+type Error struct {
+	// Status is the HTTP status code applicable to this problem.
+	Status int `json:"status"`
+	// Title is a short, human-readable summary of the problem that should not change
+	// from occurrence to occurrence of the problem.
+	Title string `json:"title"`
+	// Code is an application-specific error code, expressed as a string value.
+	Code string `json:"code"`
+	// Severity is the error's severity, as returned by errdetail.SeverityOf.
+	// It is rendered only by a Mapper created with the WithSeverity option.
+	Severity string `json:"severity,omitempty"`
+	// Details represents explanations specific to this occurrence of the problem.
+	// They are encoded as PublicDetail.
+	Details []errdetail.Detail `json:"details,omitempty"`
+}
+
+// MarshalJSON is the `json.Marshaler` interface implementation for Error.
+// The details are encoded as PublicDetail.
+func (e Error) MarshalJSON() ([]byte, error) {
+	type plain Error
+
+	return json.Marshal(struct {
+		plain
+		Details []PublicDetail `json:"details,omitempty"`
+	}{
+		plain:   plain(e),
+		Details: NewPublicDetails(e.Details),
+	})
+}
+
+// PublicDetail is the representation of a detail in client-facing responses.
+// Unlike the JSON encoding of errdetail.Detail, it holds only the rendered
+// description and reason, but not their templates, template arguments and
+// localized message references, which are meant for services and translators.
+type PublicDetail struct {
+	Domain      string          `json:"domain,omitempty"`
+	Code        string          `json:"code,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Field       string          `json:"field,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+	HelpURL     string          `json:"help_url,omitempty"`
+	Meta        errdetail.Meta  `json:"meta,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Visibility  string          `json:"visibility,omitempty"`
+	Severity    string          `json:"severity,omitempty"`
+}
+
+// NewPublicDetails converts the details into PublicDetail values, they are
+// redacted by errdetail.RedactDetails. A payload that can not be encoded to
+// JSON is omitted. Returns nil if there are no details.
+func NewPublicDetails(details []errdetail.Detail) []PublicDetail {
+	details = errdetail.RedactDetails(details)
+	if len(details) == 0 {
+		return nil
+	}
+
+	public := make([]PublicDetail, len(details))
+	for i := range details {
+		public[i] = PublicDetail{
+			Domain:      details[i].Domain(),
+			Code:        details[i].Code(),
+			Description: details[i].Description(),
+			Field:       details[i].Field(),
+			Reason:      details[i].Reason(),
+			HelpURL:     details[i].HelpURL(),
+			Meta:        details[i].Meta(),
+			Severity:    details[i].Severity().String(),
+		}
+
+		if details[i].Visibility() != errdetail.VisibilityPublic {
+			public[i].Visibility = details[i].Visibility().String()
+		}
+
+		if payload, ok := errdetail.Payload[interface{}](details[i]); ok {
+			public[i].Payload, _ = json.Marshal(payload)
+		}
+	}
+
+	return public
+}
+
+// defaultMapper is used by the package level functions.
+var defaultMapper = NewMapper() //nolint:gochecknoglobals // immutable after creation
+
+// NewErrorResponse creates an ErrorResponse with properly filled fields
+// using the default Mapper.
+func NewErrorResponse(err error) ErrorResponse {
+	return defaultMapper.NewErrorResponse(err)
+}
+
+// WriteError writes the error as a JSON encoded ErrorResponse using
+// the default Mapper.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	defaultMapper.WriteError(w, r, err)
+}
+
+// NewErrorResponse creates an ErrorResponse with properly filled fields.
+// The details are taken from the public view of the error, see WithInternals,
+// and redacted by errdetail.RedactDetails.
+func (m *Mapper) NewErrorResponse(err error) ErrorResponse {
+	if err == nil {
+		return ErrorResponse{}
+	}
+
+	err = m.view(err)
+	kind, mapping := m.Map(err)
+
+	response := ErrorResponse{
+		Error: &Error{
+			Status:  mapping.Status,
+			Title:   kind.String(),
+			Code:    mapping.Code,
+			Details: errdetail.RedactDetails(errdetail.ExtractDetails(err)),
+		},
+	}
+
+	if m.severity {
+		response.Error.Severity = errdetail.SeverityOf(err).String()
+	}
+
+	return response
+}
+
+// NewLocalizedErrorResponse creates an ErrorResponse with the title and
+// the details localized into the language by the Mapper's bundle. Without
+// a bundle, it is the same as NewErrorResponse.
+func (m *Mapper) NewLocalizedErrorResponse(err error, lang string) ErrorResponse {
+	response := m.NewErrorResponse(err)
+	if response.Error == nil || m.bundle == nil {
+		return response
+	}
+
+	err = m.view(err)
+	kind, _ := m.Map(err)
+
+	response.Error.Title = m.bundle.LocalizeKind(kind, lang)
+	response.Error.Details = errdetail.RedactDetails(m.bundle.Localize(err, lang))
+
+	return response
+}
+
+// WriteError writes the error as a JSON encoded ErrorResponse with the
+// status code the error is mapped to. If the Mapper has a bundle, the
+// response is localized into the language negotiated by the request's
+// Accept-Language header, which is reported by the Content-Language header.
+// The body is omitted for HEAD requests. Nothing is written if the error is nil.
+func (m *Mapper) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	var response ErrorResponse
+
+	if m.bundle != nil {
+		lang := m.bundle.Match(r.Header.Get("Accept-Language"))
+		response = m.NewLocalizedErrorResponse(err, lang)
+
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+	} else {
+		response = m.NewErrorResponse(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(response.Error.Status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// HandlerFunc is an HTTP handler that returns an error instead of writing it.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP is the `http.Handler` interface implementation for HandlerFunc.
+// A returned error is written by WriteError.
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defaultMapper.Handler(h).ServeHTTP(w, r)
+}
+
+// Handler adapts the handler to the `http.Handler` interface. A returned
+// error is written by the Mapper's WriteError.
+func (m *Mapper) Handler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			m.WriteError(w, r, err)
+		}
+	})
+}
@@ -0,0 +1,735 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package httperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/i18n"
+
+	. "github.com/dnozdrin/errdetail/httperr"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		file string
+	}{
+		"no_error": {
+			err:  nil,
+			file: "no_error",
+		},
+		"bad_request": {
+			err: errdetail.Wrap(
+				errdetail.ErrInvalidArgument,
+				"bad request",
+				errdetail.NewDetail(
+					errdetail.WithDomain("user.auth"),
+					errdetail.WithCode("invalid_email"),
+					errdetail.WithDescription("email validation failed"),
+					errdetail.WithField("user.email"),
+					errdetail.WithReason("an invalid character has been detected in the provided sequence"),
+					errdetail.WithMeta(errdetail.Meta{
+						"link": "https://example.com",
+						"translations": map[string]string{
+							"en": "Hello world!",
+							"ua": "Привіт, світе!",
+						},
+					}),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDomain("user.auth"),
+					errdetail.WithCode("invalid_password"),
+					errdetail.WithDescription("password validation failed"),
+					errdetail.WithField("user.password"),
+					errdetail.WithReason("password is empty"),
+				),
+			),
+			file: "bad_request",
+		},
+		"failed_precondition": {
+			err: errdetail.Wrap(
+				errdetail.ErrFailedPrecondition,
+				"precondition failed",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "failed_precondition",
+		},
+		"out_of_range": {
+			err: errdetail.Wrap(
+				errdetail.ErrOutOfRange,
+				"out of range",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "out_of_range",
+		},
+		"unauthenticated": {
+			err: errdetail.Wrap(
+				errdetail.ErrUnauthenticated,
+				"unauthenticated",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "unauthenticated",
+		},
+		"permission_denied": {
+			err: errdetail.Wrap(
+				errdetail.ErrPermissionDenied,
+				"permission denied",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "permission_denied",
+		},
+		"not_found/simple": {
+			err:  errdetail.ErrNotFound,
+			file: "not_found_simple",
+		},
+		"not_found/full": {
+			err: errdetail.Wrap(
+				errdetail.ErrNotFound,
+				"not found full",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+					errdetail.WithDescription("dummy_description_1"),
+					errdetail.WithField("dummy_field_1"),
+					errdetail.WithReason("dummy_reason_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_2"),
+					errdetail.WithCode("dummy_code_2"),
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_3"),
+					errdetail.WithCode("dummy_code_3"),
+					errdetail.WithDescription("dummy_description_3"),
+					errdetail.WithField("dummy_field_3"),
+					errdetail.WithReason("dummy_reason_3"),
+				),
+			),
+			file: "not_found_full",
+		},
+		"not_found/wrapped": {
+			err:  fmt.Errorf("test error: %w", errdetail.Wrap(errdetail.ErrNotFound, "not found")),
+			file: "not_found_wrapped",
+		},
+		"not_found/double_wrapped": {
+			err:  fmt.Errorf("test error: %w", errdetail.Wrap(fmt.Errorf("%w", errdetail.ErrNotFound), "not found")),
+			file: "not_found_wrapped",
+		},
+		"aborted": {
+			err: errdetail.Wrap(
+				errdetail.ErrAborted,
+				"aborted",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "aborted",
+		},
+		"already_exists": {
+			err: errdetail.Wrap(
+				errdetail.ErrAlreadyExists,
+				"already exists",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "already_exists",
+		},
+		"removed": {
+			err: errdetail.Wrap(
+				errdetail.ErrRemoved,
+				"removed",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "removed",
+		},
+		"resource_exhausted": {
+			err: errdetail.Wrap(
+				errdetail.ErrResourceExhausted,
+				"resource exhausted",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "resource_exhausted",
+		},
+		"data_corrupted": {
+			err: errdetail.Wrap(
+				errdetail.ErrDataCorrupted,
+				"data corrupted",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "data_corrupted",
+		},
+		"internal": {
+			err: errdetail.Wrap(
+				errdetail.ErrInternal,
+				"internal",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "internal",
+		},
+		"unknown/wrapped": {
+			err: errdetail.Wrap(
+				assert.AnError,
+				"dummy",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "unknown",
+		},
+		"unknown/new": {
+			err: errdetail.New(
+				"dummy",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "unknown",
+		},
+		"not_implemented": {
+			err: errdetail.Wrap(
+				errdetail.ErrNotImplemented,
+				"not implemented",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "not_implemented",
+		},
+		"unavailable": {
+			err: errdetail.Wrap(
+				errdetail.ErrUnavailable,
+				"unavailable",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "unavailable",
+		},
+		"deadline_exceeded/context": {
+			err: errdetail.Wrap(
+				context.DeadlineExceeded,
+				"deadline exceeded",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "deadline_exceeded",
+		},
+		"deadline_exceeded/predefined": {
+			err: errdetail.Wrap(
+				errdetail.ErrDeadlineExceeded,
+				"deadline exceeded",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "deadline_exceeded",
+		},
+		"cancelled/context": {
+			err: errdetail.Wrap(
+				context.Canceled,
+				"cancelled",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "cancelled",
+		},
+		"cancelled/predefined": {
+			err: errdetail.Wrap(
+				errdetail.ErrCancelled,
+				"cancelled",
+				errdetail.NewDetail(
+					errdetail.WithDomain("dummy_domain_1"),
+					errdetail.WithCode("dummy_code_1"),
+				),
+				errdetail.NewDetail(
+					errdetail.WithDescription("dummy_description_2"),
+					errdetail.WithField("dummy_field_2"),
+					errdetail.WithReason("dummy_reason_2"),
+				),
+			),
+			file: "cancelled",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := NewErrorResponse(tt.err)
+
+			actual, err := json.Marshal(got)
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile("testdata/" + tt.file + ".json")
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method string
+		err    error
+		status int
+		body   string
+	}{
+		"no_error": {
+			method: http.MethodGet,
+			err:    nil,
+			status: http.StatusOK,
+			body:   "",
+		},
+		"get": {
+			method: http.MethodGet,
+			err:    errdetail.NewNotFound("dummy message", errdetail.NewDetail(errdetail.WithCode("dummy_code"))),
+			status: http.StatusNotFound,
+			body:   `{"error": {"status": 404, "title": "not found", "code": "NOT_FOUND", "details": [{"code": "dummy_code"}]}}`,
+		},
+		"templates": {
+			method: http.MethodGet,
+			err: errdetail.NewInvalidArgument("invalid email", errdetail.NewDetail(
+				errdetail.WithCode("invalid_email"),
+				errdetail.WithReasonTemplate("invalid character detected: {{.char}}",
+					errdetail.Meta{"char": "#", "internal_sql": "select * from users"}),
+				errdetail.WithPayload([]int{4, 2}),
+			)),
+			status: http.StatusBadRequest,
+			body: `{"error": {"status": 400, "title": "invalid argument", "code": "INVALID_ARGUMENT", "details": [
+				{"code": "invalid_email", "reason": "invalid character detected: #", "payload": [4, 2]}
+			]}}`,
+		},
+		"head": {
+			method: http.MethodHead,
+			err:    errdetail.NewNotFound("dummy message"),
+			status: http.StatusNotFound,
+			body:   "",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			WriteError(rec, httptest.NewRequest(tt.method, "/", nil), tt.err)
+
+			assert.Equal(t, tt.status, rec.Code)
+
+			if tt.body == "" {
+				assert.Empty(t, rec.Body.String())
+
+				return
+			}
+
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestMapper_WriteError_localized(t *testing.T) {
+	t.Parallel()
+
+	bundle := i18n.NewBundle()
+	require.NoError(t, bundle.LoadFS(fstest.MapFS{
+		"uk.json": {Data: []byte(`{
+			"kind": {"NOT_FOUND": {"description": "не знайдено"}},
+			"order": {"not_found": {"reason": "замовлення {{.id}} не знайдено"}}
+		}`)},
+	}))
+
+	mapper := NewMapper(WithBundle(bundle))
+	err := errdetail.NewNotFound("order not found", errdetail.NewDetail(
+		errdetail.WithCode("order_not_found"),
+		errdetail.WithReason("order 42 is not found"),
+		errdetail.WithMessage("order.not_found", errdetail.Meta{"id": 42}),
+	))
+
+	tests := map[string]struct {
+		acceptLanguage string
+		language       string
+		body           string
+	}{
+		"ukrainian": {
+			acceptLanguage: "uk-UA,uk;q=0.9,en;q=0.8",
+			language:       "uk",
+			body: `{"error": {"status": 404, "title": "не знайдено", "code": "NOT_FOUND", "details": [{
+				"code": "order_not_found",
+				"reason": "замовлення 42 не знайдено"
+			}]}}`,
+		},
+		"unsupported": {
+			acceptLanguage: "de-DE",
+			language:       "en",
+			body: `{"error": {"status": 404, "title": "not found", "code": "NOT_FOUND", "details": [{
+				"code": "order_not_found",
+				"reason": "order 42 is not found"
+			}]}}`,
+		},
+		"no_header": {
+			language: "en",
+			body: `{"error": {"status": 404, "title": "not found", "code": "NOT_FOUND", "details": [{
+				"code": "order_not_found",
+				"reason": "order 42 is not found"
+			}]}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			mapper.WriteError(rec, req, err)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, tt.language, rec.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+
+	t.Run("no_bundle", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+		req.Header.Set("Accept-Language", "uk")
+
+		rec := httptest.NewRecorder()
+		NewMapper().WriteError(rec, req, err)
+
+		assert.Empty(t, rec.Header().Get("Content-Language"))
+		assert.Equal(t, NewErrorResponse(err), NewMapper().NewLocalizedErrorResponse(err, "uk"))
+	})
+}
+
+func TestMapper_NewErrorResponse_visibility(t *testing.T) {
+	t.Parallel()
+
+	public := errdetail.NewDetail(errdetail.WithCode("order_not_found"))
+	internal := errdetail.NewDetail(
+		errdetail.WithCode("shard_miss"),
+		errdetail.WithMeta(errdetail.Meta{"host": "db-3"}),
+		errdetail.WithVisibility(errdetail.VisibilityInternal),
+	)
+	err := errdetail.NewNotFound("order not found", public, internal)
+
+	tests := map[string]struct {
+		mapper *Mapper
+		want   []errdetail.Detail
+	}{
+		"public": {
+			mapper: NewMapper(),
+			want:   []errdetail.Detail{public},
+		},
+		"localized": {
+			mapper: NewMapper(WithBundle(i18n.NewBundle())),
+			want:   []errdetail.Detail{public},
+		},
+		"internals": {
+			mapper: NewMapper(WithInternals()),
+			want:   []errdetail.Detail{public, internal},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.mapper.NewErrorResponse(err).Error.Details)
+			assert.Equal(t, tt.want, tt.mapper.NewLocalizedErrorResponse(err, "uk").Error.Details)
+		})
+	}
+}
+
+func TestMapper_NewErrorResponse_severity(t *testing.T) {
+	t.Parallel()
+
+	critical := errdetail.NewDetail(errdetail.WithCode("disk_full"), errdetail.WithSeverity(errdetail.SeverityCritical))
+	internal := errdetail.NewDetail(
+		errdetail.WithCode("shard_down"),
+		errdetail.WithSeverity(errdetail.SeverityCritical),
+		errdetail.WithVisibility(errdetail.VisibilityInternal),
+	)
+
+	tests := map[string]struct {
+		mapper *Mapper
+		err    error
+		want   string
+	}{
+		"disabled": {
+			mapper: NewMapper(),
+			err:    errdetail.NewInternal("write order", critical),
+			want:   "",
+		},
+		"detail": {
+			mapper: NewMapper(WithSeverity()),
+			err:    errdetail.NewInternal("write order", critical),
+			want:   "critical",
+		},
+		"kind": {
+			mapper: NewMapper(WithSeverity()),
+			err:    errdetail.NewNotFound("order not found"),
+			want:   "info",
+		},
+		"internal_detail": {
+			mapper: NewMapper(WithSeverity()),
+			err:    errdetail.NewUnavailable("read order", internal),
+			want:   "warning",
+		},
+		"internals": {
+			mapper: NewMapper(WithSeverity(), WithInternals()),
+			err:    errdetail.NewUnavailable("read order", internal),
+			want:   "critical",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.mapper.NewErrorResponse(tt.err).Error.Severity)
+			assert.Equal(t, tt.want, tt.mapper.NewLocalizedErrorResponse(tt.err, "uk").Error.Severity)
+		})
+	}
+}
+
+// TestMapper_WriteError_redacted is not parallel, since it sets the package Redactor.
+func TestMapper_WriteError_redacted(t *testing.T) {
+	errdetail.SetRedactor(errdetail.NewRedactor(
+		errdetail.RedactKeys(errdetail.RedactDrop, "token"),
+		errdetail.RedactValues(errdetail.RedactMask, errdetail.MatchEmail()),
+	))
+	t.Cleanup(func() { errdetail.SetRedactor(nil) })
+
+	bundle := i18n.NewBundle()
+	require.NoError(t, bundle.AddMessages("uk", map[string]i18n.Message{
+		"user.email_taken": {Reason: i18n.Text{Other: "адреса {{.email}} вже використовується"}},
+	}))
+
+	err := errdetail.NewAlreadyExists("user exists", errdetail.NewDetail(
+		errdetail.WithCode("email_taken"),
+		errdetail.WithReason("jane.doe@example.com is taken"),
+		errdetail.WithMessage("user.email_taken", errdetail.Meta{"email": "jane.doe@example.com"}),
+		errdetail.WithMeta(errdetail.Meta{"token": "s3cr3t-t0k3n"}),
+	))
+
+	for name, mapper := range map[string]*Mapper{"plain": NewMapper(), "localized": NewMapper(WithBundle(bundle))} {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Header.Set("Accept-Language", "uk")
+
+		rec := httptest.NewRecorder()
+		mapper.WriteError(rec, req, err)
+
+		assert.Contains(t, rec.Body.String(), "[REDACTED]", name)
+		assert.NotContains(t, rec.Body.String(), "jane.doe@example.com", name)
+		assert.NotContains(t, rec.Body.String(), "s3cr3t-t0k3n", name)
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return errdetail.NewPermissionDenied("dummy message")
+		})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"error": {"status": 403, "title": "permission denied", "code": "PERMISSION_DENIED"}}`,
+			rec.Body.String())
+	})
+
+	t.Run("no_error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			w.WriteHeader(http.StatusAccepted)
+
+			return nil
+		})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("mapper", func(t *testing.T) {
+		t.Parallel()
+
+		mapper := NewMapper(WithMapping(errdetail.ErrNotFound, Mapping{Status: http.StatusGone, Code: "GONE"}))
+		handler := mapper.Handler(func(w http.ResponseWriter, r *http.Request) error {
+			return errdetail.NewNotFound("dummy message")
+		})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.JSONEq(t, `{"error": {"status": 410, "title": "not found", "code": "GONE"}}`, rec.Body.String())
+	})
+}
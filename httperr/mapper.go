@@ -0,0 +1,185 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package httperr
+
+import (
+	"net/http"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/i18n"
+)
+
+// Mapping represents an HTTP presentation of an error kind.
+type Mapping struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Code is the public code exposed instead of the error message.
+	Code string
+}
+
+// Mapper maps error kinds to their HTTP presentation.
+// A Mapper must be created by NewMapper.
+type Mapper struct {
+	mappings  map[errdetail.Kind]Mapping
+	fallback  Mapping
+	bundle    *i18n.Bundle
+	internals bool
+	severity  bool
+}
+
+// MapperOption is a function type for Mapper settings' setters.
+type MapperOption func(*Mapper)
+
+// WithMapping is an option for Mapper constructs that sets the mapping
+// of the kind, overriding the default one.
+func WithMapping(kind errdetail.Kind, mapping Mapping) MapperOption {
+	return func(m *Mapper) {
+		m.mappings[kind] = mapping
+	}
+}
+
+// WithFallback is an option for Mapper constructs that sets the mapping
+// used for errors of unknown kind.
+func WithFallback(mapping Mapping) MapperOption {
+	return func(m *Mapper) {
+		m.fallback = mapping
+	}
+}
+
+// WithBundle is an option for Mapper constructs that enables localization
+// of the written responses. The response language is negotiated by the
+// request's Accept-Language header among the bundle's languages, the kind's
+// title and the details are localized into it. By default, responses are
+// not localized.
+func WithBundle(bundle *i18n.Bundle) MapperOption {
+	return func(m *Mapper) {
+		m.bundle = bundle
+	}
+}
+
+// WithInternals is an option for Mapper constructs that disables rendering of
+// the public view of errors, so internal details are rendered too. It is meant
+// for development environments only. By default, responses are built from
+// errdetail.PublicView.
+func WithInternals() MapperOption {
+	return func(m *Mapper) {
+		m.internals = true
+	}
+}
+
+// WithSeverity is an option for Mapper constructs that enables rendering of
+// the error's severity, see errdetail.SeverityOf. The severity is computed
+// from the rendered view of the error. By default, it is not rendered.
+func WithSeverity() MapperOption {
+	return func(m *Mapper) {
+		m.severity = true
+	}
+}
+
+// NewMapper represents a Mapper constructor. The Mapper maps each of the
+// predefined kinds to the HTTP status code it stands for and to the kind's
+// code, errors of unknown kind are mapped to http.StatusInternalServerError
+// and the UNKNOWN code. The options allow to override these mappings.
+func NewMapper(opts ...MapperOption) *Mapper {
+	mapper := &Mapper{
+		mappings: make(map[errdetail.Kind]Mapping),
+		fallback: Mapping{
+			Status: http.StatusInternalServerError,
+			Code:   errdetail.Kind("").Code(),
+		},
+	}
+
+	for kind, status := range defaultStatuses() {
+		mapper.mappings[kind] = Mapping{
+			Status: status,
+			Code:   kind.Code(),
+		}
+	}
+
+	for i := range opts {
+		opts[i](mapper)
+	}
+
+	return mapper
+}
+
+// Map returns the error's kind and its mapping. Custom kinds are mapped
+// by their nearest ancestor that has a mapping. If there is no mapping,
+// returns the zero Kind and the fallback mapping.
+func (m *Mapper) Map(err error) (errdetail.Kind, Mapping) {
+	kind := errdetail.KindOf(err).Resolve(func(kind errdetail.Kind) bool {
+		_, ok := m.mappings[kind]
+
+		return ok
+	})
+
+	if mapping, ok := m.mappings[kind]; ok {
+		return kind, mapping
+	}
+
+	return "", m.fallback
+}
+
+// view returns the error's view rendered by the Mapper.
+func (m *Mapper) view(err error) error {
+	if m.internals {
+		return err
+	}
+
+	return errdetail.PublicView(err)
+}
+
+// KindOfStatus returns the most general predefined kind that stands for the
+// HTTP status code. It allows to restore the kind of errors received from
+// other services. If there is no such kind, returns the zero Kind.
+func KindOfStatus(status int) errdetail.Kind {
+	switch status {
+	case http.StatusBadRequest:
+		return errdetail.ErrInvalidArgument
+	case http.StatusUnauthorized:
+		return errdetail.ErrUnauthenticated
+	case http.StatusForbidden:
+		return errdetail.ErrPermissionDenied
+	case http.StatusNotFound:
+		return errdetail.ErrNotFound
+	case http.StatusConflict:
+		return errdetail.ErrAborted
+	case http.StatusGone:
+		return errdetail.ErrRemoved
+	case http.StatusTooManyRequests:
+		return errdetail.ErrResourceExhausted
+	case http.StatusInternalServerError:
+		return errdetail.ErrInternal
+	case http.StatusNotImplemented:
+		return errdetail.ErrNotImplemented
+	case http.StatusServiceUnavailable:
+		return errdetail.ErrUnavailable
+	case http.StatusGatewayTimeout:
+		return errdetail.ErrDeadlineExceeded
+	default:
+		return ""
+	}
+}
+
+func defaultStatuses() map[errdetail.Kind]int {
+	return map[errdetail.Kind]int{
+		errdetail.ErrInvalidArgument:    http.StatusBadRequest,
+		errdetail.ErrFailedPrecondition: http.StatusBadRequest,
+		errdetail.ErrOutOfRange:         http.StatusBadRequest,
+		errdetail.ErrUnauthenticated:    http.StatusUnauthorized,
+		errdetail.ErrPermissionDenied:   http.StatusForbidden,
+		errdetail.ErrNotFound:           http.StatusNotFound,
+		errdetail.ErrAborted:            http.StatusConflict,
+		errdetail.ErrAlreadyExists:      http.StatusConflict,
+		errdetail.ErrRemoved:            http.StatusGone,
+		errdetail.ErrResourceExhausted:  http.StatusTooManyRequests,
+		errdetail.ErrDataCorrupted:      http.StatusInternalServerError,
+		errdetail.ErrInternal:           http.StatusInternalServerError,
+		errdetail.ErrNotImplemented:     http.StatusNotImplemented,
+		errdetail.ErrUnavailable:        http.StatusServiceUnavailable,
+		errdetail.ErrDeadlineExceeded:   http.StatusGatewayTimeout,
+		errdetail.ErrCancelled:          http.StatusGatewayTimeout,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package httperr_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dnozdrin/errdetail"
+
+	. "github.com/dnozdrin/errdetail/httperr"
+)
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var (
+	errQuotaExceeded = errdetail.DefineKind("http quota exceeded", errdetail.ErrResourceExhausted)
+	errEmailTaken    = errdetail.DefineKind("http email taken", errdetail.ErrAlreadyExists)
+	errOrphan        = errdetail.DefineKind("http orphan", "")
+)
+
+func TestMapper(t *testing.T) {
+	t.Parallel()
+
+	mapper := NewMapper(
+		WithMapping(errdetail.ErrCancelled, Mapping{Status: 499, Code: "CLIENT_CLOSED_REQUEST"}),
+		WithMapping(errEmailTaken, Mapping{Status: http.StatusConflict, Code: "EMAIL_TAKEN"}),
+		WithFallback(Mapping{Status: http.StatusBadGateway, Code: "UPSTREAM"}),
+	)
+
+	tests := map[string]struct {
+		err     error
+		kind    errdetail.Kind
+		mapping Mapping
+	}{
+		"default": {
+			err:     errdetail.NewNotFound("dummy message"),
+			kind:    errdetail.ErrNotFound,
+			mapping: Mapping{Status: http.StatusNotFound, Code: "NOT_FOUND"},
+		},
+		"overridden": {
+			err:     fmt.Errorf("dummy message: %w", errdetail.ErrCancelled),
+			kind:    errdetail.ErrCancelled,
+			mapping: Mapping{Status: 499, Code: "CLIENT_CLOSED_REQUEST"},
+		},
+		"custom/mapped": {
+			err:     errdetail.Wrap(errEmailTaken, "dummy message"),
+			kind:    errEmailTaken,
+			mapping: Mapping{Status: http.StatusConflict, Code: "EMAIL_TAKEN"},
+		},
+		"custom/resolved": {
+			err:     errdetail.Wrap(errQuotaExceeded, "dummy message"),
+			kind:    errdetail.ErrResourceExhausted,
+			mapping: Mapping{Status: http.StatusTooManyRequests, Code: "RESOURCE_EXHAUSTED"},
+		},
+		"custom/unmapped": {
+			err:     errOrphan,
+			kind:    "",
+			mapping: Mapping{Status: http.StatusBadGateway, Code: "UPSTREAM"},
+		},
+		"unknown": {
+			err:     assert.AnError,
+			kind:    "",
+			mapping: Mapping{Status: http.StatusBadGateway, Code: "UPSTREAM"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			kind, mapping := mapper.Map(tt.err)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.mapping, mapping)
+		})
+	}
+
+	t.Run("all_kinds_mapped_by_default", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kind := range errdetail.Kinds() {
+			if kind.Parent() != "" || kind == errOrphan {
+				continue
+			}
+
+			mapped, mapping := NewMapper().Map(kind)
+			assert.Equal(t, kind, mapped)
+			assert.Equal(t, kind.Code(), mapping.Code)
+		}
+	})
+}
+
+func TestKindOfStatus(t *testing.T) {
+	t.Parallel()
+
+	mapper := NewMapper()
+
+	for _, status := range []int{400, 401, 403, 404, 409, 410, 429, 500, 501, 503, 504} {
+		kind := KindOfStatus(status)
+
+		_, mapping := mapper.Map(kind)
+		assert.Equal(t, status, mapping.Status, kind)
+	}
+
+	assert.Equal(t, errdetail.Kind(""), KindOfStatus(http.StatusTeapot))
+}
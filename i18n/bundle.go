@@ -0,0 +1,389 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+
+	"github.com/dnozdrin/errdetail"
+)
+
+// Formats of message files.
+const (
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// Bundle holds the messages of the languages an application supports.
+// A Bundle must be created by NewBundle. It is safe for concurrent use.
+type Bundle struct {
+	mu          sync.RWMutex
+	defaultLang language.Tag
+	messages    map[string]map[string]compiled // keyed by language tags and message IDs
+	languages   []language.Tag
+	matcher     language.Matcher
+}
+
+// compiled is a Message with parsed templates.
+type compiled struct {
+	description forms
+	reason      forms
+}
+
+// forms are the parsed templates of a Text keyed by plural forms.
+type forms map[plural.Form]*template.Template
+
+// Option is a function type for Bundle settings' setters.
+type Option func(*Bundle)
+
+// WithDefaultLanguage is an option for Bundle constructs that sets the
+// language used when there is no message in the requested one. The
+// default language is English.
+func WithDefaultLanguage(lang string) Option {
+	return func(b *Bundle) {
+		b.defaultLang = language.Make(lang)
+	}
+}
+
+// NewBundle represents a Bundle constructor.
+func NewBundle(opts ...Option) *Bundle {
+	bundle := &Bundle{
+		defaultLang: language.English,
+		messages:    make(map[string]map[string]compiled),
+	}
+
+	for i := range opts {
+		opts[i](bundle)
+	}
+
+	bundle.languages = []language.Tag{bundle.defaultLang}
+	bundle.matcher = language.NewMatcher(bundle.languages)
+
+	return bundle
+}
+
+// AddMessages adds the messages of the language, keyed by their IDs. Messages
+// with the IDs that are already added for the language are replaced. Returns
+// an error if the language tag or one of the templates is malformed.
+func (b *Bundle) AddMessages(lang string, messages map[string]Message) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("parse language: %w", err)
+	}
+
+	parsed := make(map[string]compiled, len(messages))
+
+	for id, message := range messages {
+		description, err := compile(id, message.Description)
+		if err != nil {
+			return err
+		}
+
+		reason, err := compile(id, message.Reason)
+		if err != nil {
+			return err
+		}
+
+		parsed[id] = compiled{description: description, reason: reason}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.messages[tag.String()] == nil {
+		b.messages[tag.String()] = make(map[string]compiled, len(parsed))
+		b.addLanguage(tag)
+	}
+
+	for id, message := range parsed {
+		b.messages[tag.String()][id] = message
+	}
+
+	return nil
+}
+
+// addLanguage adds the language to the ones matched by Match.
+// The bundle must be locked by the caller.
+func (b *Bundle) addLanguage(tag language.Tag) {
+	for _, known := range b.languages {
+		if known.String() == tag.String() {
+			return
+		}
+	}
+
+	b.languages = append(b.languages, tag)
+	b.matcher = language.NewMatcher(b.languages)
+}
+
+// Languages returns the tags of the languages the bundle has messages in,
+// preceded by the default language.
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	languages := make([]string, len(b.languages))
+	for i, tag := range b.languages {
+		languages[i] = tag.String()
+	}
+
+	return languages
+}
+
+// Match returns the language of the bundle that suits best the languages
+// listed in the value of an Accept-Language header, e.g. "uk-UA, uk;q=0.9,
+// en;q=0.8". If none of the languages suits, or the value is malformed,
+// returns the default language. The returned tag is one of Languages.
+func (b *Bundle) Match(acceptLanguage string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return b.defaultLang.String()
+	}
+
+	_, index, confidence := b.matcher.Match(tags...)
+	if confidence == language.No {
+		return b.defaultLang.String()
+	}
+
+	return b.languages[index].String()
+}
+
+// LoadMessages decodes the messages of the language from data in the
+// given format, FormatJSON or FormatTOML, and adds them to the bundle.
+func (b *Bundle) LoadMessages(lang, format string, data []byte) error {
+	messages, err := decode(format, data)
+	if err != nil {
+		return err
+	}
+
+	return b.AddMessages(lang, messages)
+}
+
+// LoadFile loads the messages of the file. The file format is determined
+// by its extension, .json or .toml, and the language by the last dot
+// separated part of the file name, e.g. active.uk.json or en-US.toml.
+func (b *Bundle) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read messages: %w", err)
+	}
+
+	return b.load(filepath.Base(path), data)
+}
+
+// LoadFS loads the messages of the files matching the patterns, see
+// fs.Glob for the patterns syntax. The files are named the same way as
+// for LoadFile. Without patterns, all JSON and TOML files of the root
+// directory are loaded.
+func (b *Bundle) LoadFS(fsys fs.FS, patterns ...string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"*." + FormatJSON, "*." + FormatTOML}
+	}
+
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("match messages: %w", err)
+		}
+
+		for _, name := range names {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return fmt.Errorf("read messages: %w", err)
+			}
+
+			if err := b.load(path.Base(name), data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// load adds the messages of the file with the given name.
+func (b *Bundle) load(name string, data []byte) error {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	lang := base[strings.LastIndex(base, ".")+1:]
+
+	if err := b.LoadMessages(lang, strings.TrimPrefix(ext, "."), data); err != nil {
+		return fmt.Errorf("load %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Localize returns the error's details, as returned by errdetail.ExtractDetails,
+// localized by LocalizeDetail.
+func (b *Bundle) Localize(err error, lang string) []errdetail.Detail {
+	details := errdetail.ExtractDetails(err)
+	if details == nil {
+		return nil
+	}
+
+	localized := make([]errdetail.Detail, len(details))
+	for i := range details {
+		localized[i] = b.LocalizeDetail(details[i], lang)
+	}
+
+	return localized
+}
+
+// LocalizeDetail returns a copy of the detail with the description and the
+// reason rendered from the templates of the detail's message. The templates
+// are executed with the detail's template arguments along with the message
+// arguments, the latter take precedence. The message is
+// looked up in the language, then in its parents, e.g. uk-UA, then uk, and
+// finally in the bundle's default language. The description and the reason
+// are kept as is if the message has no template for them, the template fails,
+// or the detail has no message.
+func (b *Bundle) LocalizeDetail(detail errdetail.Detail, lang string) errdetail.Detail {
+	if detail.MessageID() == "" {
+		return detail
+	}
+
+	tag, message, ok := b.lookup(detail.MessageID(), lang)
+	if !ok {
+		return detail
+	}
+
+	args := detail.TemplateArgs()
+	if args == nil {
+		args = make(errdetail.Meta)
+	}
+
+	for key, value := range detail.MessageArgs() {
+		args[key] = value
+	}
+
+	localized := detail.Clone()
+
+	if description, ok := message.description.render(tag, args); ok {
+		errdetail.WithDescription(description)(&localized)
+	}
+
+	if reason, ok := message.reason.render(tag, args); ok {
+		errdetail.WithReason(reason)(&localized)
+	}
+
+	return localized
+}
+
+// LocalizeKind returns the name of the kind rendered from the description
+// template of the kind's message, see KindMessageID. The message is looked
+// up the same way as by LocalizeDetail. If there is no message, returns
+// the kind's String.
+func (b *Bundle) LocalizeKind(kind errdetail.Kind, lang string) string {
+	tag, message, ok := b.lookup(KindMessageID(kind), lang)
+	if !ok {
+		return kind.String()
+	}
+
+	if name, ok := message.description.render(tag, nil); ok {
+		return name
+	}
+
+	return kind.String()
+}
+
+// lookup returns the message with the ID along with the language it is found in.
+func (b *Bundle) lookup(id, lang string) (language.Tag, compiled, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, tag := range b.fallbacks(lang) {
+		if message, ok := b.messages[tag.String()][id]; ok {
+			return tag, message, true
+		}
+	}
+
+	return language.Und, compiled{}, false
+}
+
+// fallbacks returns the language followed by its parents and the default
+// language followed by its parents.
+func (b *Bundle) fallbacks(lang string) []language.Tag {
+	var tags []language.Tag
+
+	if tag, err := language.Parse(lang); err == nil {
+		tags = appendParents(tags, tag)
+	}
+
+	return appendParents(tags, b.defaultLang)
+}
+
+func appendParents(tags []language.Tag, tag language.Tag) []language.Tag {
+	for ; !tag.IsRoot(); tag = tag.Parent() {
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// compile parses the templates of the text.
+func compile(id string, text Text) (forms, error) {
+	sources := map[plural.Form]string{
+		plural.Zero:  text.Zero,
+		plural.One:   text.One,
+		plural.Two:   text.Two,
+		plural.Few:   text.Few,
+		plural.Many:  text.Many,
+		plural.Other: text.Other,
+	}
+
+	parsed := make(forms)
+
+	for form, source := range sources {
+		if source == "" {
+			continue
+		}
+
+		tmpl, err := template.New(id).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", id, err)
+		}
+
+		parsed[form] = tmpl
+	}
+
+	return parsed, nil
+}
+
+// render executes the template of the plural form selected by the count
+// argument in the language. Reports false if there is no template or
+// the template fails, e.g. refers to a missing argument.
+func (f forms) render(tag language.Tag, args errdetail.Meta) (string, bool) {
+	tmpl := f[plural.Other]
+
+	if count, found := args[CountArg]; found {
+		if selected, ok := f[pluralForm(tag, count)]; ok {
+			tmpl = selected
+		}
+	}
+
+	if tmpl == nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, args); err != nil {
+		return "", false
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,415 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package i18n_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+
+	. "github.com/dnozdrin/errdetail/i18n"
+)
+
+func newBundle(t *testing.T) *Bundle {
+	t.Helper()
+
+	bundle := NewBundle()
+	require.NoError(t, bundle.LoadFS(os.DirFS("testdata")))
+
+	return bundle
+}
+
+func TestBundle_Localize(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	emailTaken := errdetail.NewDetail(
+		errdetail.WithCode("email_taken"),
+		errdetail.WithField("user.email"),
+		errdetail.WithDescription("email is taken"),
+		errdetail.WithMessage("user.email_taken", errdetail.Meta{"email": "user@example.com"}),
+	)
+
+	tests := map[string]struct {
+		lang        string
+		detail      errdetail.Detail
+		description string
+		reason      string
+	}{
+		"english": {
+			lang:        "en",
+			detail:      emailTaken,
+			description: "email is already taken",
+			reason:      "email user@example.com is already taken",
+		},
+		"ukrainian": {
+			lang:        "uk",
+			detail:      emailTaken,
+			description: "електронна адреса вже зайнята",
+			reason:      "адреса user@example.com вже зайнята",
+		},
+		"region_falls_back_to_language": {
+			lang:        "uk-UA",
+			detail:      emailTaken,
+			description: "електронна адреса вже зайнята",
+			reason:      "адреса user@example.com вже зайнята",
+		},
+		"unknown_language_falls_back_to_default": {
+			lang:        "de-AT",
+			detail:      emailTaken,
+			description: "email is already taken",
+			reason:      "email user@example.com is already taken",
+		},
+		"malformed_language_falls_back_to_default": {
+			lang:        "!!",
+			detail:      emailTaken,
+			description: "email is already taken",
+			reason:      "email user@example.com is already taken",
+		},
+		"missing_message_falls_back_to_default": {
+			lang:        "uk",
+			detail:      errdetail.NewDetail(errdetail.WithMessage("order.not_found", nil), errdetail.WithReason("id 1")),
+			description: "order is not found",
+			reason:      "id 1",
+		},
+		"unknown_message": {
+			lang:        "uk",
+			detail:      errdetail.NewDetail(errdetail.WithMessage("order.paid", nil), errdetail.WithReason("paid")),
+			description: "",
+			reason:      "paid",
+		},
+		"no_message": {
+			lang:        "uk",
+			detail:      errdetail.NewDetail(errdetail.WithCode("email_taken"), errdetail.WithReason("taken")),
+			description: "",
+			reason:      "taken",
+		},
+		"template_arguments": {
+			lang: "uk",
+			detail: errdetail.NewDetail(
+				errdetail.WithReasonTemplate("email {{.email}} is taken", errdetail.Meta{"email": "a@b.c"}),
+				errdetail.WithMessage("user.email_taken", nil),
+			),
+			description: "електронна адреса вже зайнята",
+			reason:      "адреса a@b.c вже зайнята",
+		},
+		"message_arguments_take_precedence": {
+			lang: "en",
+			detail: errdetail.NewDetail(
+				errdetail.WithReasonTemplate("email {{.email}} is taken", errdetail.Meta{"email": "a@b.c"}),
+				errdetail.WithMessage("user.email_taken", errdetail.Meta{"email": "d@e.f"}),
+			),
+			description: "email is already taken",
+			reason:      "email d@e.f is already taken",
+		},
+		"missing_argument": {
+			lang: "uk",
+			detail: errdetail.NewDetail(
+				errdetail.WithReason("email is taken"),
+				errdetail.WithMessage("user.email_taken", nil),
+			),
+			description: "електронна адреса вже зайнята",
+			reason:      "email is taken",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			details := bundle.Localize(errdetail.NewAlreadyExists("user exists", tt.detail), tt.lang)
+			require.Len(t, details, 1)
+
+			assert.Equal(t, tt.description, details[0].Description())
+			assert.Equal(t, tt.reason, details[0].Reason())
+			assert.Equal(t, tt.detail.Code(), details[0].Code())
+			assert.Equal(t, tt.detail.Field(), details[0].Field())
+			assert.Equal(t, tt.detail.MessageID(), details[0].MessageID())
+		})
+	}
+
+	t.Run("error_unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		err := errdetail.NewAlreadyExists("user exists", emailTaken)
+		bundle.Localize(err, "uk")
+
+		assert.Equal(t, []errdetail.Detail{emailTaken}, errdetail.ExtractDetails(err))
+	})
+
+	t.Run("no_details", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, bundle.Localize(errdetail.ErrNotFound, "uk"))
+		assert.Nil(t, bundle.Localize(nil, "uk"))
+	})
+}
+
+func TestBundle_Localize_plural(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	tests := map[string]struct {
+		lang   string
+		count  interface{}
+		reason string
+	}{
+		"en_one": {
+			lang:   "en",
+			count:  1,
+			reason: "password must have at least 1 character",
+		},
+		"en_other": {
+			lang:   "en",
+			count:  uint8(8),
+			reason: "password must have at least 8 characters",
+		},
+		"en_decimal_one": {
+			lang:   "en",
+			count:  "1.0",
+			reason: "password must have at least 1.0 characters",
+		},
+		"uk_one": {
+			lang:   "uk",
+			count:  21,
+			reason: "пароль має містити щонайменше 21 символ",
+		},
+		"uk_few": {
+			lang:   "uk-UA",
+			count:  int64(3),
+			reason: "пароль має містити щонайменше 3 символи",
+		},
+		"uk_many": {
+			lang:   "uk",
+			count:  11,
+			reason: "пароль має містити щонайменше 11 символів",
+		},
+		"uk_other": {
+			lang:   "uk",
+			count:  1.5,
+			reason: "пароль має містити щонайменше 1.5 символу",
+		},
+		"not_a_number": {
+			lang:   "uk",
+			count:  "a few",
+			reason: "пароль має містити щонайменше a few символу",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			detail := bundle.LocalizeDetail(errdetail.NewDetail(
+				errdetail.WithMessage("user.password_too_short", errdetail.Meta{"count": tt.count}),
+			), tt.lang)
+
+			assert.Equal(t, tt.reason, detail.Reason())
+		})
+	}
+}
+
+func TestBundle_WithDefaultLanguage(t *testing.T) {
+	t.Parallel()
+
+	bundle := NewBundle(WithDefaultLanguage("uk"))
+	require.NoError(t, bundle.LoadFile("testdata/active.uk.toml"))
+	require.NoError(t, bundle.LoadFile("testdata/active.en.json"))
+
+	detail := errdetail.NewDetail(errdetail.WithMessage("user.email_taken", errdetail.Meta{"email": "a@b.c"}))
+
+	german := bundle.LocalizeDetail(detail, "de")
+	assert.Equal(t, "адреса a@b.c вже зайнята", german.Reason())
+
+	british := bundle.LocalizeDetail(detail, "en-GB")
+	assert.Equal(t, "email a@b.c is already taken", british.Reason())
+}
+
+func TestBundle_AddMessages(t *testing.T) {
+	t.Parallel()
+
+	bundle := NewBundle()
+	require.NoError(t, bundle.AddMessages("en", map[string]Message{
+		"greeting": {Description: Text{Other: "hello"}},
+	}))
+	require.NoError(t, bundle.AddMessages("en", map[string]Message{
+		"farewell": {Reason: Text{One: "one bye", Other: "{{.count}} byes"}},
+	}))
+
+	greeting := bundle.LocalizeDetail(errdetail.NewDetail(errdetail.WithMessage("greeting", nil)), "en")
+	assert.Equal(t, "hello", greeting.Description())
+
+	farewell := bundle.LocalizeDetail(errdetail.NewDetail(errdetail.WithMessage("farewell", errdetail.Meta{"count": 2})), "en")
+	assert.Equal(t, "2 byes", farewell.Reason())
+
+	assert.EqualError(t, bundle.AddMessages("en", map[string]Message{
+		"broken": {Reason: Text{Other: "{{.count"}},
+	}), `message "broken": template: broken:1: unclosed action`)
+
+	assert.Error(t, bundle.AddMessages("not a language", nil))
+}
+
+func TestBundle_LoadFS(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		files    fstest.MapFS
+		patterns []string
+		err      string
+	}{
+		"patterns": {
+			files: fstest.MapFS{
+				"locales/en.json":   {Data: []byte(`{"a": {"description": "a"}}`)},
+				"locales/uk.toml":   {Data: []byte(`a.description = "а"`)},
+				"locales/README.md": {Data: []byte(`# Messages`)},
+			},
+			patterns: []string{"locales/*.json", "locales/*.toml"},
+		},
+		"unknown_format": {
+			files:    fstest.MapFS{"en.yaml": {Data: []byte(`a: {description: a}`)}},
+			patterns: []string{"*"},
+			err:      `load en.yaml: unknown messages format "yaml"`,
+		},
+		"malformed_json": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{`)}},
+			err:   "load en.json: decode messages: unexpected end of JSON input",
+		},
+		"malformed_language": {
+			files: fstest.MapFS{"messages.json": {Data: []byte(`{}`)}},
+			err:   "load messages.json: parse language: language: tag is not well-formed",
+		},
+		"not_an_object": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{"a": "a"}`)}},
+			err:   `load en.json: message "a": expected an object, got string`,
+		},
+		"unknown_key": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{"a": {"description": "a", "title": "a"}}`)}},
+			err:   `load en.json: message "a": unknown key "title"`,
+		},
+		"unknown_plural_form": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{"a": {"reason": {"single": "a"}}}`)}},
+			err:   `load en.json: message "a": reason: unknown plural form "single"`,
+		},
+		"not_a_string_form": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{"a": {"reason": {"one": 1}}}`)}},
+			err:   `load en.json: message "a": reason: plural form "one": expected a string, got float64`,
+		},
+		"not_a_text": {
+			files: fstest.MapFS{"en.json": {Data: []byte(`{"a": {"reason": 1}}`)}},
+			err:   `load en.json: message "a": reason: expected a string or plural forms, got float64`,
+		},
+		"bad_pattern": {
+			files:    fstest.MapFS{},
+			patterns: []string{"["},
+			err:      "match messages: syntax error in pattern",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			bundle := NewBundle()
+
+			err := bundle.LoadFS(tt.files, tt.patterns...)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			detail := errdetail.NewDetail(errdetail.WithMessage("a", nil))
+
+			ukrainian := bundle.LocalizeDetail(detail, "uk")
+			assert.Equal(t, "а", ukrainian.Description())
+
+			english := bundle.LocalizeDetail(detail, "en")
+			assert.Equal(t, "a", english.Description())
+		})
+	}
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, NewBundle().LoadFile("testdata/active.de.json"))
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	require.NoError(t, LoadFS(os.DirFS("testdata"), "*.toml"))
+	require.NoError(t, LoadFile("testdata/active.en.json"))
+	require.NoError(t, AddMessages("uk", map[string]Message{
+		"order.not_found": {Description: Text{Other: "замовлення не знайдено"}},
+	}))
+
+	details := Localize(errdetail.NewNotFound("order not found",
+		errdetail.NewDetail(errdetail.WithMessage("order.not_found", nil)),
+	), "uk")
+
+	require.Len(t, details, 1)
+	assert.Equal(t, "замовлення не знайдено", details[0].Description())
+}
+
+func TestBundle_Match(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+	require.NoError(t, bundle.AddMessages("pt-BR", nil))
+	require.NoError(t, bundle.AddMessages("uk", nil))
+
+	assert.Equal(t, []string{"en", "uk", "pt-BR"}, bundle.Languages())
+
+	tests := map[string]string{
+		"":                          "en",
+		"uk":                        "uk",
+		"uk-UA":                     "uk",
+		"uk-UA, uk;q=0.9, en;q=0.8": "uk",
+		"de-DE, en;q=0.5, uk;q=0.1": "en",
+		"fr, uk;q=0.5":              "uk",
+		"pt":                        "pt-BR",
+		"de":                        "en",
+		"*":                         "en",
+		"en-GB;q=x":                 "en",
+	}
+
+	for header, want := range tests {
+		header, want := header, want
+
+		t.Run(header, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, want, bundle.Match(header))
+		})
+	}
+}
+
+func TestBundle_LocalizeKind(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	assert.Equal(t, "kind.NOT_FOUND", KindMessageID(errdetail.ErrNotFound))
+	assert.Equal(t, "kind.UNKNOWN", KindMessageID(""))
+
+	assert.Equal(t, "не знайдено", bundle.LocalizeKind(errdetail.ErrNotFound, "uk-UA"))
+	assert.Equal(t, "not found", bundle.LocalizeKind(errdetail.ErrNotFound, "en"))
+	assert.Equal(t, "aborted", bundle.LocalizeKind(errdetail.ErrAborted, "uk"))
+	assert.Equal(t, "unknown", bundle.LocalizeKind("", "uk"))
+}
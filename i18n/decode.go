@@ -0,0 +1,138 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Keys of messages and plural forms in message files.
+const (
+	descriptionKey = "description"
+	reasonKey      = "reason"
+)
+
+// decode decodes the messages of a file in the given format.
+func decode(format string, data []byte) (map[string]Message, error) {
+	var raw map[string]interface{}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("decode messages: %w", err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("decode messages: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown messages format %q", format)
+	}
+
+	messages := make(map[string]Message)
+	if err := collect(messages, "", raw); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// collect adds the messages of the table to the collected ones. A table
+// that has the description or the reason key is a message, other tables
+// are groups of messages with the IDs prefixed by the group name.
+func collect(collected map[string]Message, prefix string, table map[string]interface{}) error {
+	for name, value := range table {
+		id := prefix + name
+
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("message %q: expected an object, got %T", id, value)
+		}
+
+		_, hasDescription := entry[descriptionKey]
+		_, hasReason := entry[reasonKey]
+
+		if !hasDescription && !hasReason {
+			if err := collect(collected, id+".", entry); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		message, err := decodeMessage(id, entry)
+		if err != nil {
+			return err
+		}
+
+		collected[id] = message
+	}
+
+	return nil
+}
+
+func decodeMessage(id string, entry map[string]interface{}) (Message, error) {
+	var message Message
+
+	for key, value := range entry {
+		var target *Text
+
+		switch key {
+		case descriptionKey:
+			target = &message.Description
+		case reasonKey:
+			target = &message.Reason
+		default:
+			return Message{}, fmt.Errorf("message %q: unknown key %q", id, key)
+		}
+
+		text, err := decodeText(value)
+		if err != nil {
+			return Message{}, fmt.Errorf("message %q: %s: %w", id, key, err)
+		}
+
+		*target = text
+	}
+
+	return message, nil
+}
+
+// decodeText decodes either a string or an object of plural forms.
+func decodeText(value interface{}) (Text, error) {
+	switch value := value.(type) {
+	case string:
+		return Text{Other: value}, nil
+	case map[string]interface{}:
+		var text Text
+
+		for form, source := range value {
+			s, ok := source.(string)
+			if !ok {
+				return Text{}, fmt.Errorf("plural form %q: expected a string, got %T", form, source)
+			}
+
+			target, ok := map[string]*string{
+				"zero":  &text.Zero,
+				"one":   &text.One,
+				"two":   &text.Two,
+				"few":   &text.Few,
+				"many":  &text.Many,
+				"other": &text.Other,
+			}[form]
+			if !ok {
+				return Text{}, fmt.Errorf("unknown plural form %q", form)
+			}
+
+			*target = s
+		}
+
+		return text, nil
+	default:
+		return Text{}, fmt.Errorf("expected a string or plural forms, got %T", value)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package i18n localizes the descriptions and the reasons of details.
+//
+// A detail refers to its message by an ID set by errdetail.WithMessage,
+// along with the arguments of the message templates:
+//
+//	errdetail.NewDetail(
+//		errdetail.WithCode("password_too_short"),
+//		errdetail.WithMessage("user.password_too_short", errdetail.Meta{"count": 8}),
+//	)
+//
+// Messages are loaded into a Bundle from JSON or TOML files, one file per
+// language. A message has the description and the reason templates, each
+// of them is either a text/template string or a set of CLDR plural forms
+// selected by the "count" argument:
+//
+//	{
+//	  "user": {
+//	    "password_too_short": {
+//	      "description": "пароль закороткий",
+//	      "reason": {
+//	        "one": "пароль має містити щонайменше {{.count}} символ",
+//	        "few": "пароль має містити щонайменше {{.count}} символи",
+//	        "many": "пароль має містити щонайменше {{.count}} символів",
+//	        "other": "пароль має містити щонайменше {{.count}} символу"
+//	      }
+//	    }
+//	  }
+//	}
+//
+// Nested objects are joined into dot separated message IDs, so the message
+// above has the user.password_too_short ID.
+//
+// The names of kinds are localized by the descriptions of the messages
+// with the kind. prefix followed by the kind's code, e.g. kind.NOT_FOUND.
+package i18n
+
+import (
+	"io/fs"
+
+	"github.com/dnozdrin/errdetail"
+)
+
+const (
+	// CountArg is the name of the message argument that selects plural forms.
+	CountArg = "count"
+	// kindPrefix is the prefix of the kinds' message IDs.
+	kindPrefix = "kind."
+)
+
+// KindMessageID returns the ID of the message that holds the localized name
+// of the kind in its description, e.g. kind.NOT_FOUND.
+func KindMessageID(kind errdetail.Kind) string {
+	return kindPrefix + kind.Code()
+}
+
+// Message is a localized message of a detail.
+type Message struct {
+	// Description is the template of the detail's description.
+	Description Text
+	// Reason is the template of the detail's reason.
+	Reason Text
+}
+
+// Text is a text/template string with its CLDR plural forms. The form is
+// selected by the CountArg argument of a detail's message, Other is used
+// if there is no such argument or no text for the selected form.
+type Text struct {
+	Zero  string
+	One   string
+	Two   string
+	Few   string
+	Many  string
+	Other string
+}
+
+// defaultBundle is used by the package level functions.
+var defaultBundle = NewBundle() //nolint:gochecknoglobals // the default bundle is shared by the package
+
+// AddMessages adds the messages of the language to the default bundle.
+func AddMessages(lang string, messages map[string]Message) error {
+	return defaultBundle.AddMessages(lang, messages)
+}
+
+// LoadFile loads the messages of the file into the default bundle.
+func LoadFile(path string) error {
+	return defaultBundle.LoadFile(path)
+}
+
+// LoadFS loads the messages of the files matching the patterns into the default bundle.
+func LoadFS(fsys fs.FS, patterns ...string) error {
+	return defaultBundle.LoadFS(fsys, patterns...)
+}
+
+// Localize returns the error's details localized by the default bundle.
+func Localize(err error, lang string) []errdetail.Detail {
+	return defaultBundle.Localize(err, lang)
+}
+
+// Match returns the language of the default bundle that suits best the
+// value of an Accept-Language header.
+func Match(acceptLanguage string) string {
+	return defaultBundle.Match(acceptLanguage)
+}
+
+// Default returns the default bundle used by the package level functions.
+func Default() *Bundle {
+	return defaultBundle
+}
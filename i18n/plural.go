@@ -0,0 +1,101 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package i18n
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// pluralForm returns the CLDR cardinal plural form of the count in the
+// language. The count is either a number or its decimal representation,
+// e.g. "1.50", which is distinguished from 1.5 by the plural rules of
+// some languages. Other is returned for values that are not numbers.
+func pluralForm(tag language.Tag, count interface{}) plural.Form {
+	decimal, ok := decimalOf(count)
+	if !ok {
+		return plural.Other
+	}
+
+	op, ok := operands(decimal)
+	if !ok {
+		return plural.Other
+	}
+
+	return plural.Cardinal.MatchPlural(tag, op.i, op.v, op.w, op.f, op.t)
+}
+
+// decimalOf returns the decimal representation of the number.
+func decimalOf(count interface{}) (string, bool) {
+	switch count := count.(type) {
+	case string:
+		return count, true
+	case json.Number:
+		return count.String(), true
+	}
+
+	value := reflect.ValueOf(count)
+
+	switch value.Kind() { //nolint:exhaustive // other kinds are not numbers
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// pluralOperands are the CLDR plural operands of a decimal number:
+//
+//	i - the integer digits;
+//	v - the number of visible fraction digits, with trailing zeros;
+//	w - the number of visible fraction digits, without trailing zeros;
+//	f - the visible fraction digits, with trailing zeros;
+//	t - the visible fraction digits, without trailing zeros.
+//
+// For details see https://unicode.org/reports/tr35/tr35-numbers.html#Operands.
+type pluralOperands struct {
+	i, v, w, f, t int
+}
+
+// operands returns the plural operands of the decimal number.
+func operands(decimal string) (pluralOperands, bool) {
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(decimal, "-"), ".")
+	trimmed := strings.TrimRight(fraction, "0")
+
+	op := pluralOperands{v: len(fraction), w: len(trimmed)}
+
+	numbers := [...]struct {
+		digits string
+		target *int
+	}{
+		{digits: integer, target: &op.i},
+		{digits: fraction, target: &op.f},
+		{digits: trimmed, target: &op.t},
+	}
+
+	for _, number := range numbers {
+		if number.digits == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(number.digits)
+		if err != nil || strings.ContainsAny(number.digits, "+-") {
+			return pluralOperands{}, false
+		}
+
+		*number.target = n
+	}
+
+	return op, integer != ""
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const joinSeparator = "; "
+
+// Join allows to combine multiple errors into one error that holds details
+// of all of them. Nil errors are discarded, Join returns nil if there are
+// no errors left. The error message is a combination of the errors' messages
+// prefixed by msg, if it is not empty.
+func Join(msg string, errs ...error) error {
+	joined := &joinError{}
+
+	msgs := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		joined.errs = append(joined.errs, err)
+		joined.details = append(joined.details, collectDetails(err)...)
+		msgs = append(msgs, err.Error())
+	}
+
+	if len(joined.errs) == 0 {
+		return nil
+	}
+
+	joined.text = msg
+	joined.msg = strings.Join(msgs, joinSeparator)
+
+	if msg != "" {
+		joined.msg = fmt.Sprintf("%s: %s", msg, joined.msg)
+	}
+
+	joined.details = filter(joined.details)
+
+	return joined
+}
+
+type joinError struct {
+	// msg is the full error message, text is the message passed to Join.
+	msg     string
+	text    string
+	errs    []error
+	details []Detail
+	// visibility is the visibility of text.
+	visibility Visibility
+}
+
+// Error returns error message.
+func (err *joinError) Error() string {
+	return err.msg
+}
+
+// Is reports whether any error in the joined errors' trees matches target.
+func (err *joinError) Is(target error) bool {
+	for _, e := range err.errs {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in the joined errors' trees that matches target.
+func (err *joinError) As(target interface{}) bool {
+	for _, e := range err.errs {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unwrap returns the joined errors.
+func (err *joinError) Unwrap() []error {
+	return err.errs
+}
+
+// Details returns details of all joined errors.
+// It returns a copy, so the error can not be modified by the caller.
+func (err *joinError) Details() []Detail {
+	return copyDetails(err.details)
+}
+
+// Format is the `fmt.Formatter` interface implementation for joined errors.
+// It supports the same verbs as errors created by New and Wrap do. The %+v
+// verb prints each of the joined errors indented.
+func (err *joinError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			writeLayers(s, err)
+		case s.Flag('#'):
+			fmt.Fprintf(s, "&errdetail.joinError{msg:%q, errs:%s, details:%#v}",
+				RedactText(err.msg), RedactText(fmt.Sprintf("%#v", err.errs)), RedactDetails(err.details))
+		default:
+			_, _ = io.WriteString(s, RedactText(err.msg))
+		}
+	case 's':
+		_, _ = io.WriteString(s, RedactText(err.msg))
+	case 'q':
+		fmt.Fprintf(s, "%q", RedactText(err.msg))
+	default:
+		fmt.Fprintf(s, "%%!%c(*errdetail.joinError=%s)", verb, RedactText(err.msg))
+	}
+}
+
+// MarshalJSON is the `json.Marshaler` interface implementation for joined
+// errors. The encoded error has the same shape as errors created by New
+// and Wrap have.
+func (err *joinError) MarshalJSON() ([]byte, error) {
+	return marshalError(err, err.msg, err.details)
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+//go:build go1.20
+// +build go1.20
+
+package errdetail_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestStdLibJoin(t *testing.T) {
+	t.Parallel()
+
+	nameDetail := NewDetail(WithField("user.name"), WithCode("required"))
+	emailDetail := NewDetail(WithField("user.email"), WithCode("invalid_email"))
+
+	nameErr := NewInvalidArgument("name is empty", nameDetail)
+	emailErr := NewInvalidArgument("email is invalid", emailDetail)
+
+	t.Run("errors_join", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.Join(nameErr, emailErr)
+
+		assert.Equal(t, []Detail{nameDetail}, ExtractDetails(err))
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractAllDetails(err))
+	})
+
+	t.Run("errorf_multiple_w", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("validation failed: %w, %w", nameErr, emailErr)
+
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractAllDetails(err))
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(errors.Join(nameErr, emailErr), "validation failed")
+
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractDetails(err))
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+		assert.Equal(t, "validation failed: name is empty: invalid argument\n"+
+			"email is invalid: invalid argument\n"+
+			"    name is empty: invalid argument\n"+
+			"        - code: required\n"+
+			"          field: user.name\n"+
+			"    invalid argument\n"+
+			"    email is invalid: invalid argument\n"+
+			"        - code: invalid_email\n"+
+			"          field: user.email\n"+
+			"    invalid argument",
+			fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("joined_by_this_lib", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.Join(Join("", nameErr), emailErr)
+
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractAllDetails(err))
+	})
+}
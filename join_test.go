@@ -0,0 +1,160 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	nameDetail := NewDetail(WithField("user.name"), WithCode("required"))
+	emailDetail := NewDetail(WithField("user.email"), WithCode("invalid_email"))
+	extraDetail := NewDetail(WithCode("dummy_code"))
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, Join("dummy message"))
+		assert.NoError(t, Join("dummy message", nil, nil))
+	})
+
+	t.Run("joined", func(t *testing.T) {
+		t.Parallel()
+
+		nameErr := NewInvalidArgument("name is empty", nameDetail)
+		emailErr := Wrap(fmt.Errorf("%w", NewInvalidArgument("email is invalid", emailDetail)), "")
+
+		err := Join("validation failed", nameErr, nil, emailErr, assert.AnError)
+		require.Error(t, err)
+
+		assert.Equal(t, "validation failed: name is empty: invalid argument; "+
+			"email is invalid: invalid argument; "+assert.AnError.Error(), err.Error())
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+		assert.ErrorIs(t, err, nameErr)
+		assert.ErrorIs(t, err, emailErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.False(t, errors.Is(err, ErrNotFound))
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractDetails(err))
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractAllDetails(err))
+
+		var d detailedError
+		assert.True(t, errors.As(err, &d))
+
+		multi, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // the interface is checked
+		require.True(t, ok)
+		assert.Equal(t, []error{nameErr, emailErr, assert.AnError}, multi.Unwrap())
+	})
+
+	t.Run("empty_message", func(t *testing.T) {
+		t.Parallel()
+
+		err := Join("", New("first"), New("second"))
+		assert.EqualError(t, err, "first; second")
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		joined := Join("", New("first", nameDetail), New("second", emailDetail))
+		err := Wrap(joined, "outer", extraDetail)
+
+		assert.Equal(t, []Detail{nameDetail, emailDetail, extraDetail}, ExtractDetails(err))
+		assert.Equal(t, []Detail{nameDetail, emailDetail, extraDetail}, ExtractAllDetails(err))
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		shared := New("shared", nameDetail)
+		err := Join("", shared, Wrap(shared, "wrapped", emailDetail))
+
+		assert.Equal(t, []Detail{nameDetail, nameDetail, emailDetail}, ExtractDetails(err))
+		assert.Equal(t, []Detail{nameDetail, emailDetail}, ExtractAllDetails(err))
+	})
+
+	t.Run("format", func(t *testing.T) {
+		t.Parallel()
+
+		err := Join("validation failed",
+			NewInvalidArgument("name is empty", nameDetail),
+			New("email is invalid", emailDetail),
+		)
+
+		assert.Equal(t, "validation failed: name is empty: invalid argument; email is invalid", fmt.Sprintf("%v", err))
+		assert.Equal(t, "validation failed: name is empty: invalid argument; email is invalid", fmt.Sprintf("%s", err))
+		assert.Equal(t, `"validation failed: name is empty: invalid argument; email is invalid"`, fmt.Sprintf("%q", err))
+		assert.Equal(t, "validation failed: name is empty: invalid argument; email is invalid\n"+
+			"    name is empty: invalid argument\n"+
+			"        - code: required\n"+
+			"          field: user.name\n"+
+			"    invalid argument\n"+
+			"    email is invalid\n"+
+			"        - code: invalid_email\n"+
+			"          field: user.email",
+			fmt.Sprintf("%+v", err))
+		assert.Contains(t, fmt.Sprintf("%#v", err), `&errdetail.joinError{msg:"validation failed: `)
+		assert.Equal(t, "%!d(*errdetail.joinError=email is invalid)", fmt.Sprintf("%d", Join("", New("email is invalid"))))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		err := Join("validation failed", NewInvalidArgument("name is empty", nameDetail))
+
+		encoded, marshalErr := json.Marshal(err)
+		require.NoError(t, marshalErr)
+
+		decoded, decodeErr := Decode(encoded)
+		require.NoError(t, decodeErr)
+
+		assert.Equal(t, err.Error(), decoded.Error())
+		assert.ErrorIs(t, decoded, ErrInvalidArgument)
+		assert.Equal(t, []Detail{nameDetail}, ExtractDetails(decoded))
+	})
+}
+
+func TestExtractAllDetails(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithCode("dummy_code"))
+
+	tests := map[string]struct {
+		err  error
+		want []Detail
+	}{
+		"no_error": {
+			err:  nil,
+			want: nil,
+		},
+		"no_details_in_error": {
+			err:  assert.AnError,
+			want: nil,
+		},
+		"with_details": {
+			err:  fmt.Errorf("dummy message: %w", New("dummy message", detail, detail)),
+			want: []Detail{detail},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ExtractAllDetails(tt.err))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// detailJSON is the wire representation of a Detail.
+type detailJSON struct {
+	Domain              string          `json:"domain,omitempty"`
+	Code                string          `json:"code,omitempty"`
+	Description         string          `json:"description,omitempty"`
+	Field               string          `json:"field,omitempty"`
+	Reason              string          `json:"reason,omitempty"`
+	DescriptionTemplate string          `json:"description_template,omitempty"`
+	ReasonTemplate      string          `json:"reason_template,omitempty"`
+	TemplateArgs        Meta            `json:"template_args,omitempty"`
+	HelpURL             string          `json:"help_url,omitempty"`
+	MessageID           string          `json:"message_id,omitempty"`
+	MessageArgs         Meta            `json:"message_args,omitempty"`
+	Meta                Meta            `json:"meta,omitempty"`
+	Payload             json.RawMessage `json:"payload,omitempty"`
+	Visibility          string          `json:"visibility,omitempty"`
+	Severity            string          `json:"severity,omitempty"`
+}
+
+// errorJSON is the wire representation of an error created by New or Wrap.
+type errorJSON struct {
+	Message string   `json:"message"`
+	Kind    string   `json:"kind,omitempty"`
+	Details []Detail `json:"details,omitempty"`
+}
+
+// MarshalJSON is the `json.Marshaler` interface implementation for Detail.
+// Empty fields are omitted, the payload is encoded to the payload member,
+// and the visibility is encoded only for internal details. The detail is
+// redacted by the package Redactor, see SetRedactor.
+// The description and the reason are encoded rendered, their templates
+// are encoded along with the template arguments.
+func (d Detail) MarshalJSON() ([]byte, error) {
+	d = Redact(d)
+
+	encoded := detailJSON{
+		Domain:              d.domain,
+		Code:                d.code,
+		Description:         d.Description(),
+		Field:               d.field,
+		Reason:              d.Reason(),
+		DescriptionTemplate: d.descriptionTemplate,
+		ReasonTemplate:      d.reasonTemplate,
+		TemplateArgs:        d.templateArgs,
+		HelpURL:             d.helpURL,
+		MessageID:           d.messageID,
+		MessageArgs:         d.messageArgs,
+		Meta:                d.meta,
+		Visibility:          visibilityName(d.visibility),
+		Severity:            d.severity.String(),
+	}
+
+	if d.payload != nil {
+		payload, err := json.Marshal(d.payload)
+		if err != nil {
+			return nil, fmt.Errorf("encode detail payload: %w", err)
+		}
+
+		encoded.Payload = payload
+	}
+
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON is the `json.Unmarshaler` interface implementation for Detail.
+// The payload is kept encoded until it is read by Payload. The rendered
+// description and reason are decoded as plain text, the templates are not
+// decoded, since templates received from other parties must not be executed.
+// The template arguments are decoded for translators.
+func (d *Detail) UnmarshalJSON(data []byte) error {
+	var decoded detailJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("decode detail: %w", err)
+	}
+
+	*d = NewDetail(
+		WithDomain(decoded.Domain),
+		WithCode(decoded.Code),
+		WithDescription(decoded.Description),
+		WithField(decoded.Field),
+		WithReason(decoded.Reason),
+		WithHelpURL(decoded.HelpURL),
+		WithMessage(decoded.MessageID, decoded.MessageArgs),
+		WithMeta(decoded.Meta),
+		WithVisibility(visibilityOf(decoded.Visibility)),
+		WithSeverity(severityOf(decoded.Severity)),
+	)
+
+	d.addTemplateArgs(decoded.TemplateArgs)
+
+	if len(decoded.Payload) != 0 {
+		WithPayload(decoded.Payload)(d)
+	}
+
+	return nil
+}
+
+// MarshalJSON is the `json.Marshaler` interface implementation for
+// errors created by New, Wrap and the predefined errors constructors.
+//
+// The error is encoded as an object with the next members:
+//
+//	message - the error message, as returned by Error(), redacted by RedactText;
+//	kind    - the code of the error's kind, as returned by KindOf, if any;
+//	details - the error's details, if any.
+func (err *wrapper) MarshalJSON() ([]byte, error) {
+	return marshalError(err, err.msg, err.details)
+}
+
+func marshalError(err error, msg string, details []Detail) ([]byte, error) {
+	encoded := errorJSON{
+		Message: RedactText(msg),
+		Details: details,
+	}
+
+	if kind := KindOf(err); kind != "" {
+		encoded.Kind = kind.Code()
+	}
+
+	return json.Marshal(encoded)
+}
+
+// Decode restores an error from its JSON representation produced by
+// marshaling an error created by New or Wrap. The restored error returns
+// the same message and details as the original one, and matches the same
+// kind by errors.Is. Decode returns nil for the JSON null.
+func Decode(data []byte) (error, error) {
+	var decoded *errorJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+
+	if decoded == nil {
+		return nil, nil
+	}
+
+	kind, _ := lookupKind(decoded.Kind)
+
+	return Restore(decoded.Message, kind, decoded.Details...), nil
+}
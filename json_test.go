@@ -0,0 +1,195 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestDetailJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		detail Detail
+		json   string
+	}{
+		"full": {
+			detail: NewDetail(
+				WithDomain("user.auth"),
+				WithCode("invalid_email"),
+				WithDescription("email validation failed"),
+				WithField("user.email"),
+				WithReason("invalid character detected"),
+				WithHelpURL("https://example.com/errors#invalid_email"),
+				WithMeta(Meta{"link": "https://example.com"}),
+			),
+			json: `{
+				"domain": "user.auth",
+				"code": "invalid_email",
+				"description": "email validation failed",
+				"field": "user.email",
+				"reason": "invalid character detected",
+				"help_url": "https://example.com/errors#invalid_email",
+				"meta": {"link": "https://example.com"}
+			}`,
+		},
+		"partial": {
+			detail: NewDetail(WithCode("dummy_code")),
+			json:   `{"code": "dummy_code"}`,
+		},
+		"internal": {
+			detail: NewDetail(WithCode("shard_miss"), WithVisibility(VisibilityInternal)),
+			json:   `{"code": "shard_miss", "visibility": "internal"}`,
+		},
+		"severity": {
+			detail: NewDetail(WithCode("disk_full"), WithSeverity(SeverityCritical)),
+			json:   `{"code": "disk_full", "severity": "critical"}`,
+		},
+		"empty": {
+			detail: NewDetail(),
+			json:   `{}`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(tt.detail)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(encoded))
+
+			var decoded Detail
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+			assert.Equal(t, tt.detail, decoded)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var decoded Detail
+		assert.Error(t, json.Unmarshal([]byte(`{"code": 1}`), &decoded))
+	})
+}
+
+func TestErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		kind error
+		file string
+	}{
+		"full": {
+			err: NewInvalidArgument(
+				"bad request",
+				NewDetail(
+					WithDomain("user.auth"),
+					WithCode("invalid_email"),
+					WithDescription("email validation failed"),
+					WithField("user.email"),
+					WithReason("invalid character detected: \"#\""),
+					WithMeta(Meta{
+						"link":     "https://example.com",
+						"attempts": float64(2),
+					}),
+				),
+				NewDetail(
+					WithDomain("user.auth"),
+					WithCode("invalid_password"),
+					WithDescription("password validation failed"),
+					WithField("user.password"),
+					WithReason("password is empty"),
+				),
+			),
+			kind: ErrInvalidArgument,
+			file: "error_full",
+		},
+		"message_only": {
+			err:  NewNotFound("discount not found"),
+			kind: ErrNotFound,
+			file: "error_message_only",
+		},
+		"wrapped_by_std_lib": {
+			err:  Wrap(fmt.Errorf("%w", ErrNotFound), "discount not found"),
+			kind: ErrNotFound,
+			file: "error_message_only",
+		},
+		"unknown": {
+			err:  New("dummy message", NewDetail(WithCode("dummy_code"))),
+			kind: nil,
+			file: "error_unknown",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(tt.err)
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile("testdata/" + tt.file + ".json")
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(encoded))
+
+			decoded, err := Decode(encoded)
+			require.NoError(t, err)
+			require.Error(t, decoded)
+
+			assert.Equal(t, tt.err.Error(), decoded.Error())
+			assert.Equal(t, ExtractDetails(tt.err), ExtractDetails(decoded))
+
+			if tt.kind != nil {
+				assert.ErrorIs(t, decoded, tt.kind)
+			} else {
+				assert.Nil(t, errors.Unwrap(decoded))
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("null", func(t *testing.T) {
+		t.Parallel()
+
+		decoded, err := Decode([]byte(`null`))
+		assert.NoError(t, err)
+		assert.NoError(t, decoded)
+	})
+
+	t.Run("unknown_kind", func(t *testing.T) {
+		t.Parallel()
+
+		decoded, err := Decode([]byte(`{"message": "dummy message", "kind": "dummy kind"}`))
+		require.NoError(t, err)
+		assert.EqualError(t, decoded, "dummy message")
+		assert.Nil(t, errors.Unwrap(decoded))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		decoded, err := Decode([]byte(`{"message": 1}`))
+		assert.Error(t, err)
+		assert.NoError(t, decoded)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package jsonapi renders detailed errors as JSON:API error objects
+// and parses such objects back into errors.
+// For details see https://jsonapi.org/format/#error-objects.
+package jsonapi
+
+import (
+	"strings"
+)
+
+// ContentType is the media type of JSON:API documents.
+const ContentType = "application/vnd.api+json"
+
+// Document represents a JSON:API top-level document that contains errors.
+type Document struct {
+	// Errors are the error objects, one per each of the error's details.
+	Errors []Error `json:"errors,omitempty"`
+	// Meta holds the error message under the "message" key.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Error represents a JSON:API error object.
+type Error struct {
+	// ID is a unique identifier for this particular occurrence of the problem.
+	ID string `json:"id,omitempty"`
+	// Links hold the links to further details about the problem.
+	Links *Links `json:"links,omitempty"`
+	// Status is the HTTP status code applicable to this problem, expressed as a string value.
+	Status string `json:"status,omitempty"`
+	// Code is an application-specific error code, expressed as a string value.
+	Code string `json:"code,omitempty"`
+	// Title is a short, human-readable summary of the problem.
+	Title string `json:"title,omitempty"`
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Source holds references to the primary source of the error.
+	Source *Source `json:"source,omitempty"`
+	// Meta contains non-standard meta-information about the error.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Links represents the links of an error object.
+type Links struct {
+	// About is a link that leads to further details about this particular occurrence of the problem.
+	About string `json:"about,omitempty"`
+	// Type is a link that identifies the type of error that this particular error is an instance of.
+	Type string `json:"type,omitempty"`
+}
+
+// Source represents references to the primary source of an error.
+type Source struct {
+	// Pointer is a JSON Pointer to the value in the request document that caused the error.
+	Pointer string `json:"pointer,omitempty"`
+	// Parameter is the name of the URI query parameter that caused the error.
+	Parameter string `json:"parameter,omitempty"`
+	// Header is the name of the request header that caused the error.
+	Header string `json:"header,omitempty"`
+}
+
+const attributesPointer = "/data/attributes/"
+
+// PointerSource converts a field name into a Source with a JSON Pointer to
+// the request document's attribute. Nested fields are separated by dots,
+// e.g. the user.email field stands for /data/attributes/user/email. Field
+// names that start with a slash are considered as pointers already.
+func PointerSource(field string) Source {
+	if strings.HasPrefix(field, "/") {
+		return Source{Pointer: field}
+	}
+
+	segments := strings.Split(field, ".")
+	for i := range segments {
+		segments[i] = pointerEscaper().Replace(segments[i])
+	}
+
+	return Source{Pointer: attributesPointer + strings.Join(segments, "/")}
+}
+
+// SourceField converts a Source back into a field name. It is the inverse
+// of PointerSource for pointers, parameter and header names are returned
+// as is.
+func SourceField(source Source) string {
+	switch {
+	case source.Pointer == "":
+		if source.Parameter != "" {
+			return source.Parameter
+		}
+
+		return source.Header
+	case strings.HasPrefix(source.Pointer, attributesPointer):
+		segments := strings.Split(strings.TrimPrefix(source.Pointer, attributesPointer), "/")
+		for i := range segments {
+			segments[i] = pointerUnescaper().Replace(segments[i])
+		}
+
+		return strings.Join(segments, ".")
+	default:
+		return source.Pointer
+	}
+}
+
+func pointerEscaper() *strings.Replacer {
+	return strings.NewReplacer("~", "~0", "/", "~1")
+}
+
+func pointerUnescaper() *strings.Replacer {
+	return strings.NewReplacer("~1", "/", "~0", "~")
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/dnozdrin/errdetail/jsonapi"
+)
+
+func TestPointerSource(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field string
+		want  Source
+	}{
+		"plain": {
+			field: "email",
+			want:  Source{Pointer: "/data/attributes/email"},
+		},
+		"nested": {
+			field: "user.address.city",
+			want:  Source{Pointer: "/data/attributes/user/address/city"},
+		},
+		"escaped": {
+			field: "a/b.c~d",
+			want:  Source{Pointer: "/data/attributes/a~1b/c~0d"},
+		},
+		"pointer": {
+			field: "/data/relationships/author",
+			want:  Source{Pointer: "/data/relationships/author"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := PointerSource(tt.field)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.field, SourceField(got))
+		})
+	}
+}
+
+func TestSourceField(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		source Source
+		want   string
+	}{
+		"parameter": {
+			source: Source{Parameter: "page[size]"},
+			want:   "page[size]",
+		},
+		"header": {
+			source: Source{Header: "If-Match"},
+			want:   "If-Match",
+		},
+		"empty": {
+			source: Source{},
+			want:   "",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, SourceField(tt.source))
+		})
+	}
+}
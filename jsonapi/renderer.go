@@ -0,0 +1,388 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+)
+
+const (
+	// DefaultTypeBaseURI is the default prefix of error type links.
+	DefaultTypeBaseURI = "urn:errdetail:kind:"
+
+	messageKey  = "message"
+	severityKey = "severity"
+	// domainKey is namespaced, so it does not clash with the detail's Meta entries.
+	domainKey = "errdetail.domain"
+)
+
+// Renderer converts errors into JSON:API documents and back.
+// A Renderer must be created by NewRenderer.
+type Renderer struct {
+	mapper   *httperr.Mapper
+	typeBase string
+	newID    func() string
+	about    func(errdetail.Detail) string
+	toSource func(field string) Source
+	toField  func(Source) string
+	// internals disables rendering of the public view of errors.
+	internals bool
+	// severity enables rendering of the error's severity.
+	severity bool
+}
+
+// Option is a function type for Renderer settings' setters.
+type Option func(*Renderer)
+
+// WithMapper is an option for Renderer constructs that sets the Mapper
+// used to determine the error objects' status.
+func WithMapper(mapper *httperr.Mapper) Option {
+	return func(r *Renderer) {
+		r.mapper = mapper
+	}
+}
+
+// WithTypeBaseURI is an option for Renderer constructs that sets the prefix
+// of the error type links. A type link is the prefix followed by the error
+// kind's code in lower case, with underscores replaced by hyphens.
+func WithTypeBaseURI(base string) Option {
+	return func(r *Renderer) {
+		r.typeBase = base
+	}
+}
+
+// WithIDs is an option for Renderer constructs that sets the generator
+// of the error objects' identifiers. By default, identifiers are omitted.
+func WithIDs(newID func() string) Option {
+	return func(r *Renderer) {
+		r.newID = newID
+	}
+}
+
+// WithAboutLinks is an option for Renderer constructs that sets the function
+// that returns the about link of a detail. By default, the detail's help URL
+// is used.
+func WithAboutLinks(about func(errdetail.Detail) string) Option {
+	return func(r *Renderer) {
+		r.about = about
+	}
+}
+
+// WithSources is an option for Renderer constructs that sets conversions
+// between detail fields and error sources. By default, PointerSource and
+// SourceField are used.
+func WithSources(toSource func(field string) Source, toField func(Source) string) Option {
+	return func(r *Renderer) {
+		r.toSource = toSource
+		r.toField = toField
+	}
+}
+
+// WithInternals is an option for Renderer constructs that disables rendering
+// of the public view of errors, so the full error messages and internal details
+// are rendered. It is meant for development environments only. By default,
+// documents are built from errdetail.PublicView.
+func WithInternals() Option {
+	return func(r *Renderer) {
+		r.internals = true
+	}
+}
+
+// WithSeverity is an option for Renderer constructs that enables rendering
+// of the error's severity, see errdetail.SeverityOf, into the document's
+// meta. By default, it is not rendered.
+func WithSeverity() Option {
+	return func(r *Renderer) {
+		r.severity = true
+	}
+}
+
+// NewRenderer represents a Renderer constructor.
+func NewRenderer(opts ...Option) *Renderer {
+	renderer := &Renderer{
+		mapper:   httperr.NewMapper(),
+		typeBase: DefaultTypeBaseURI,
+		newID: func() string {
+			return ""
+		},
+		about: func(detail errdetail.Detail) string {
+			return detail.HelpURL()
+		},
+		toSource: PointerSource,
+		toField:  SourceField,
+	}
+
+	for i := range opts {
+		opts[i](renderer)
+	}
+
+	return renderer
+}
+
+// defaultRenderer is used by the package level functions.
+var defaultRenderer = NewRenderer() //nolint:gochecknoglobals // immutable after creation
+
+// NewDocument converts the error into a Document using the default Renderer.
+func NewDocument(err error) Document {
+	return defaultRenderer.NewDocument(err)
+}
+
+// WriteError writes the error as a JSON:API document using the default Renderer.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	defaultRenderer.WriteError(w, r, err)
+}
+
+// Parse decodes a JSON:API document into an error using the default Renderer.
+func Parse(data []byte) (error, error) {
+	return defaultRenderer.Parse(data)
+}
+
+// NewDocument converts the error into a Document with an error object per
+// each of the error's details. The code, the title, the detail and the source
+// of an object are taken from the detail's code, description, reason and field.
+// The detail's Meta is put into the object's meta, as well as its domain under
+// the errdetail.domain key. The status and the type link of all objects are
+// determined by the error's kind. An error
+// without details is converted into a single object with the code and the name
+// of its kind.
+// The details are redacted by errdetail.RedactDetails, and the error message
+// by errdetail.RedactText.
+// The error message, if any, is put into the document's meta, as well as the
+// error's severity if WithSeverity is set. Unless WithInternals
+// is set, the public view of the error is converted, see errdetail.PublicView.
+func (r *Renderer) NewDocument(err error) Document {
+	if err == nil {
+		return Document{}
+	}
+
+	if !r.internals {
+		err = errdetail.PublicView(err)
+	}
+
+	kind := errdetail.KindOf(err)
+	_, mapping := r.mapper.Map(err)
+	status := strconv.Itoa(mapping.Status)
+
+	var document Document
+	if msg := errdetail.RedactText(err.Error()); msg != "" {
+		document.Meta = map[string]interface{}{messageKey: msg}
+	}
+
+	if r.severity {
+		if document.Meta == nil {
+			document.Meta = make(map[string]interface{}, 1)
+		}
+
+		document.Meta[severityKey] = errdetail.SeverityOf(err).String()
+	}
+
+	details := errdetail.RedactDetails(errdetail.ExtractDetails(err))
+	if len(details) == 0 {
+		document.Errors = []Error{{
+			ID:     r.newID(),
+			Links:  r.links(kind, ""),
+			Status: status,
+			Code:   kind.Code(),
+			Title:  kind.String(),
+		}}
+
+		return document
+	}
+
+	document.Errors = make([]Error, len(details))
+	for i := range details {
+		document.Errors[i] = Error{
+			ID:     r.newID(),
+			Links:  r.links(kind, r.about(details[i])),
+			Status: status,
+			Code:   details[i].Code(),
+			Title:  details[i].Description(),
+			Detail: details[i].Reason(),
+			Source: r.source(details[i].Field()),
+			Meta:   meta(details[i]),
+		}
+	}
+
+	return document
+}
+
+// WriteError writes the error as a JSON:API document with the status code
+// the error is mapped to. The body is omitted for HEAD requests. Nothing is
+// written if the error is nil.
+func (r *Renderer) WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	_, mapping := r.mapper.Map(err)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(mapping.Status)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(r.NewDocument(err))
+}
+
+// Error converts the Document back into an error. The error's kind is
+// determined by the first object's type link, or by its status if the type
+// is unknown. The error message is taken from the document's meta, or from
+// the first object's title if there is no message. Returns nil if the
+// Document has no error objects.
+func (r *Renderer) Error(document Document) error {
+	if len(document.Errors) == 0 {
+		return nil
+	}
+
+	kind := r.kindOf(document.Errors[0])
+
+	message, _ := document.Meta[messageKey].(string)
+	if message == "" {
+		message = document.Errors[0].Title
+	}
+
+	var details []errdetail.Detail
+
+	for i := range document.Errors {
+		if !isKindObject(kind, document.Errors[i]) {
+			details = append(details, r.detail(document.Errors[i]))
+		}
+	}
+
+	return errdetail.Restore(message, kind, details...)
+}
+
+// Parse decodes a JSON:API document and converts it into an error.
+func (r *Renderer) Parse(data []byte) (error, error) {
+	var document Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parse document: %w", err)
+	}
+
+	return r.Error(document), nil
+}
+
+func (r *Renderer) links(kind errdetail.Kind, about string) *Links {
+	links := Links{
+		About: about,
+		Type:  r.typeOf(kind),
+	}
+
+	if links == (Links{}) {
+		return nil
+	}
+
+	return &links
+}
+
+// typeOf returns the type link of the kind.
+func (r *Renderer) typeOf(kind errdetail.Kind) string {
+	if kind == "" || r.typeBase == "" {
+		return ""
+	}
+
+	return r.typeBase + strings.ToLower(strings.ReplaceAll(kind.Code(), "_", "-"))
+}
+
+func (r *Renderer) source(field string) *Source {
+	if field == "" {
+		return nil
+	}
+
+	source := r.toSource(field)
+
+	return &source
+}
+
+// meta returns a copy of the detail's Meta with the domain added.
+func meta(detail errdetail.Detail) map[string]interface{} {
+	result := map[string]interface{}(detail.Meta())
+	if len(result) == 0 && detail.Domain() == "" {
+		return nil
+	}
+
+	if result == nil {
+		result = make(map[string]interface{}, 1)
+	}
+
+	if detail.Domain() != "" {
+		result[domainKey] = detail.Domain()
+	}
+
+	return result
+}
+
+// kindOf returns the kind of the error object.
+func (r *Renderer) kindOf(object Error) errdetail.Kind {
+	if object.Links != nil && object.Links.Type != "" {
+		for _, kind := range errdetail.Kinds() {
+			if r.typeOf(kind) == object.Links.Type {
+				return kind
+			}
+		}
+	}
+
+	status, _ := strconv.Atoi(object.Status)
+
+	return httperr.KindOfStatus(status)
+}
+
+// isKindObject reports whether the object describes the error's kind
+// rather than one of its details.
+func isKindObject(kind errdetail.Kind, object Error) bool {
+	return object.Code == kind.Code() && object.Title == kind.String() &&
+		object.Detail == "" && object.Source == nil && object.Meta == nil
+}
+
+// detail converts the error object back into a detail.
+func (r *Renderer) detail(object Error) errdetail.Detail {
+	var field, helpURL string
+	if object.Source != nil {
+		field = r.toField(*object.Source)
+	}
+
+	if object.Links != nil {
+		helpURL = object.Links.About
+	}
+
+	var (
+		domain string
+		meta   errdetail.Meta
+	)
+
+	for key, value := range object.Meta {
+		if s, ok := value.(string); ok && key == domainKey {
+			domain = s
+
+			continue
+		}
+
+		if meta == nil {
+			meta = make(errdetail.Meta, len(object.Meta))
+		}
+
+		meta[key] = value
+	}
+
+	return errdetail.NewDetail(
+		errdetail.WithDomain(domain),
+		errdetail.WithCode(object.Code),
+		errdetail.WithDescription(object.Title),
+		errdetail.WithField(field),
+		errdetail.WithReason(object.Detail),
+		errdetail.WithHelpURL(helpURL),
+		errdetail.WithMeta(meta),
+	)
+}
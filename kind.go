@@ -0,0 +1,246 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// unknownKind is the name of the zero Kind.
+const unknownKind = "unknown"
+
+// Kind represents a class of errors, such as ErrNotFound or ErrInternal.
+// The zero Kind stands for errors of unknown kind. Custom kinds can be
+// created by DefineKind.
+type Kind string
+
+// kinds is the registry of kinds created by DefineKind.
+var kinds = struct { //nolint:gochecknoglobals // the registry is shared by the package
+	sync.RWMutex
+	defined []Kind
+	parents map[Kind]Kind
+}{
+	parents: make(map[Kind]Kind),
+}
+
+// DefineKind creates a custom kind with the given name. The custom kind
+// matches its parent and the parent's ancestors by errors.Is, while staying
+// distinguishable from them by KindOf. A parent may be one of the predefined
+// kinds, a kind created by DefineKind, or the zero Kind for kinds that have
+// no parent. DefineKind is intended to be called on a package initialization,
+// it panics if the name has neither letters nor digits, the name or its code
+// is already taken, or the parent is unknown.
+func DefineKind(name string, parent Kind) Kind {
+	kind := Kind(name)
+	if codeOf(name) == "" {
+		panic(fmt.Sprintf("errdetail: kind name %q has no letters or digits", name))
+	}
+
+	kinds.Lock()
+	defer kinds.Unlock()
+
+	known := append(predefinedKinds(), kinds.defined...)
+	for _, k := range append(known, "") {
+		if k.Code() == kind.Code() {
+			panic(fmt.Sprintf("errdetail: kind %q is already defined", name))
+		}
+	}
+
+	if !isKnownKind(parent) {
+		panic(fmt.Sprintf("errdetail: parent kind %q of %q is unknown", string(parent), name))
+	}
+
+	kinds.defined = append(kinds.defined, kind)
+	kinds.parents[kind] = parent
+
+	return kind
+}
+
+// isKnownKind reports whether the kind is either zero, predefined or defined.
+// The registry must be locked by the caller.
+func isKnownKind(kind Kind) bool {
+	if _, ok := kinds.parents[kind]; ok || kind == "" {
+		return true
+	}
+
+	return isPredefinedKind(kind)
+}
+
+// Error is the `error` interface implementation for the Kind type.
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// String returns a human-readable name of the kind.
+func (k Kind) String() string {
+	if k == "" {
+		return unknownKind
+	}
+
+	return string(k)
+}
+
+// Code returns a stable machine-readable code of the kind, such as NOT_FOUND.
+// The code of a custom kind is its name in upper case, with every sequence
+// of characters other than letters and digits replaced by an underscore.
+func (k Kind) Code() string {
+	switch k {
+	case ErrInvalidArgument:
+		return "INVALID_ARGUMENT"
+	case ErrFailedPrecondition:
+		return "FAILED_PRECONDITION"
+	case ErrOutOfRange:
+		return "OUT_OF_RANGE"
+	case ErrUnauthenticated:
+		return "UNAUTHENTICATED"
+	case ErrPermissionDenied:
+		return "PERMISSION_DENIED"
+	case ErrNotFound:
+		return "NOT_FOUND"
+	case ErrAborted:
+		return "ABORTED"
+	case ErrAlreadyExists:
+		return "ALREADY_EXISTS"
+	case ErrRemoved:
+		return "REMOVED"
+	case ErrResourceExhausted:
+		return "RESOURCE_EXHAUSTED"
+	case ErrDataCorrupted:
+		return "DATA_CORRUPTED"
+	case ErrInternal:
+		return "INTERNAL"
+	case ErrNotImplemented:
+		return "NOT_IMPLEMENTED"
+	case ErrUnavailable:
+		return "UNAVAILABLE"
+	case ErrDeadlineExceeded:
+		return "DEADLINE_EXCEEDED"
+	case ErrCancelled:
+		return "CANCELLED"
+	case "":
+		return "UNKNOWN"
+	default:
+		return codeOf(string(k))
+	}
+}
+
+// codeOf converts a kind name into a code.
+func codeOf(name string) string {
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.ToUpper(strings.Join(fields, "_"))
+}
+
+// Parent returns the parent of a custom kind. For other kinds, returns the zero Kind.
+func (k Kind) Parent() Kind {
+	kinds.RLock()
+	defer kinds.RUnlock()
+
+	return kinds.parents[k]
+}
+
+// Is reports whether the target is one of the kind's ancestors.
+// It allows errors.Is to match custom kinds against their parents.
+func (k Kind) Is(target error) bool {
+	for parent := k.Parent(); parent != ""; parent = parent.Parent() {
+		if parent == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve walks the kind and its ancestors, starting from the kind itself,
+// and returns the first one accepted by the mapped function. It allows to
+// map custom kinds by the nearest ancestor that has a mapping. If none of
+// the kinds is accepted, returns the zero Kind.
+func (k Kind) Resolve(mapped func(Kind) bool) Kind {
+	for kind := k; kind != ""; kind = kind.Parent() {
+		if mapped(kind) {
+			return kind
+		}
+	}
+
+	return ""
+}
+
+// Kinds returns all predefined kinds followed by the kinds created by DefineKind.
+func Kinds() []Kind {
+	kinds.RLock()
+	defer kinds.RUnlock()
+
+	return append(predefinedKinds(), kinds.defined...)
+}
+
+// isPredefinedKind reports whether the kind is one of the predefined kinds.
+func isPredefinedKind(kind Kind) bool {
+	for _, predefined := range predefinedKinds() {
+		if kind == predefined {
+			return true
+		}
+	}
+
+	return false
+}
+
+func predefinedKinds() []Kind {
+	return []Kind{
+		ErrInvalidArgument,
+		ErrFailedPrecondition,
+		ErrOutOfRange,
+		ErrUnauthenticated,
+		ErrPermissionDenied,
+		ErrNotFound,
+		ErrAborted,
+		ErrAlreadyExists,
+		ErrRemoved,
+		ErrResourceExhausted,
+		ErrDataCorrupted,
+		ErrInternal,
+		ErrNotImplemented,
+		ErrUnavailable,
+		ErrDeadlineExceeded,
+		ErrCancelled,
+	}
+}
+
+// KindOf returns the first kind found in the error's chain. The context.Canceled
+// and context.DeadlineExceeded errors are reported as ErrCancelled and
+// ErrDeadlineExceeded respectively. If no kind is found, returns the zero Kind.
+// For custom kinds, the custom kind itself is returned, Kind.Resolve allows
+// to find its nearest ancestor that has a mapping.
+func KindOf(err error) Kind {
+	var kind Kind
+
+	switch {
+	case errors.As(err, &kind):
+		return kind
+	case errors.Is(err, context.Canceled):
+		return ErrCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrDeadlineExceeded
+	default:
+		return ""
+	}
+}
+
+// lookupKind returns the kind with the given code, if any.
+func lookupKind(code string) (Kind, bool) {
+	for _, kind := range Kinds() {
+		if kind.Code() == code {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
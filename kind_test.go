@@ -0,0 +1,235 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestKind(t *testing.T) {
+	t.Parallel()
+
+	tests := map[Kind]struct {
+		name string
+		code string
+	}{
+		ErrInvalidArgument:    {name: "invalid argument", code: "INVALID_ARGUMENT"},
+		ErrFailedPrecondition: {name: "precondition failed", code: "FAILED_PRECONDITION"},
+		ErrOutOfRange:         {name: "out of range", code: "OUT_OF_RANGE"},
+		ErrUnauthenticated:    {name: "unauthenticated", code: "UNAUTHENTICATED"},
+		ErrPermissionDenied:   {name: "permission denied", code: "PERMISSION_DENIED"},
+		ErrNotFound:           {name: "not found", code: "NOT_FOUND"},
+		ErrAborted:            {name: "aborted", code: "ABORTED"},
+		ErrAlreadyExists:      {name: "already exists", code: "ALREADY_EXISTS"},
+		ErrRemoved:            {name: "removed", code: "REMOVED"},
+		ErrResourceExhausted:  {name: "resource exhausted", code: "RESOURCE_EXHAUSTED"},
+		ErrDataCorrupted:      {name: "data corrupted", code: "DATA_CORRUPTED"},
+		ErrInternal:           {name: "internal", code: "INTERNAL"},
+		ErrNotImplemented:     {name: "not implemented", code: "NOT_IMPLEMENTED"},
+		ErrUnavailable:        {name: "unavailable", code: "UNAVAILABLE"},
+		ErrDeadlineExceeded:   {name: "deadline exceeded", code: "DEADLINE_EXCEEDED"},
+		ErrCancelled:          {name: "cancelled", code: "CANCELLED"},
+		"":                    {name: "unknown", code: "UNKNOWN"},
+	}
+
+	for kind, tt := range tests {
+		kind, tt := kind, tt
+
+		t.Run(tt.code, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.name, kind.String())
+			assert.Equal(t, tt.code, kind.Code())
+		})
+	}
+
+	t.Run("kinds", func(t *testing.T) {
+		t.Parallel()
+
+		predefined := Kinds()[:len(tests)-1]
+
+		for _, kind := range predefined {
+			assert.Contains(t, tests, kind)
+			assert.NotEqual(t, "UNKNOWN", kind.Code())
+		}
+	})
+}
+
+func TestKindOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		want Kind
+	}{
+		"no_error": {
+			err:  nil,
+			want: "",
+		},
+		"unknown": {
+			err:  New("dummy message"),
+			want: "",
+		},
+		"kind": {
+			err:  ErrNotFound,
+			want: ErrNotFound,
+		},
+		"constructor": {
+			err:  NewPermissionDenied("dummy message"),
+			want: ErrPermissionDenied,
+		},
+		"wrapped": {
+			err:  fmt.Errorf("dummy message: %w", Wrap(fmt.Errorf("%w", ErrAborted), "dummy message")),
+			want: ErrAborted,
+		},
+		"joined": {
+			err:  Join("dummy message", New("dummy message"), NewAlreadyExists("dummy message")),
+			want: ErrAlreadyExists,
+		},
+		"context_canceled": {
+			err:  Wrap(context.Canceled, "dummy message"),
+			want: ErrCancelled,
+		},
+		"context_deadline_exceeded": {
+			err:  fmt.Errorf("dummy message: %w", context.DeadlineExceeded),
+			want: ErrDeadlineExceeded,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+}
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var (
+	errQuotaExceeded     = DefineKind("quota exceeded", ErrResourceExhausted)
+	errDailyQuotaReached = DefineKind("daily quota reached", errQuotaExceeded)
+	errEmailTaken        = DefineKind("email taken", ErrAlreadyExists)
+	errOrphan            = DefineKind("orphan-kind #1", "")
+)
+
+func TestDefineKind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hierarchy", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, errQuotaExceeded, errDailyQuotaReached.Parent())
+		assert.Equal(t, ErrResourceExhausted, errQuotaExceeded.Parent())
+		assert.Equal(t, Kind(""), ErrResourceExhausted.Parent())
+		assert.Equal(t, Kind(""), errOrphan.Parent())
+
+		assert.ErrorIs(t, errDailyQuotaReached, ErrResourceExhausted)
+		assert.ErrorIs(t, errDailyQuotaReached, errQuotaExceeded)
+		assert.NotErrorIs(t, errQuotaExceeded, errDailyQuotaReached)
+		assert.NotErrorIs(t, ErrResourceExhausted, errQuotaExceeded)
+		assert.NotErrorIs(t, errEmailTaken, ErrResourceExhausted)
+		assert.NotErrorIs(t, errOrphan, Kind(""))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := Wrap(fmt.Errorf("%w", newQuotaError()), "dummy message")
+
+		assert.ErrorIs(t, err, ErrResourceExhausted)
+		assert.ErrorIs(t, err, errQuotaExceeded)
+		assert.NotErrorIs(t, err, errEmailTaken)
+		assert.Equal(t, errQuotaExceeded, KindOf(err))
+	})
+
+	t.Run("names", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "quota exceeded", errQuotaExceeded.String())
+		assert.Equal(t, "QUOTA_EXCEEDED", errQuotaExceeded.Code())
+		assert.Equal(t, "ORPHAN_KIND_1", errOrphan.Code())
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		t.Parallel()
+
+		kinds := Kinds()
+		assert.Equal(t, ErrInvalidArgument, kinds[0])
+		assert.Subset(t, kinds, []Kind{errQuotaExceeded, errDailyQuotaReached, errEmailTaken, errOrphan})
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		t.Parallel()
+
+		mapped := func(kinds ...Kind) func(Kind) bool {
+			return func(kind Kind) bool {
+				for _, k := range kinds {
+					if k == kind {
+						return true
+					}
+				}
+
+				return false
+			}
+		}
+
+		assert.Equal(t, errDailyQuotaReached, errDailyQuotaReached.Resolve(mapped(errDailyQuotaReached, ErrResourceExhausted)))
+		assert.Equal(t, errQuotaExceeded, errDailyQuotaReached.Resolve(mapped(errQuotaExceeded, ErrResourceExhausted)))
+		assert.Equal(t, ErrResourceExhausted, errDailyQuotaReached.Resolve(mapped(ErrResourceExhausted)))
+		assert.Equal(t, Kind(""), errDailyQuotaReached.Resolve(mapped(ErrNotFound)))
+		assert.Equal(t, Kind(""), errOrphan.Resolve(mapped(ErrNotFound)))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := json.Marshal(newQuotaError())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"message": "quota exceeded", "kind": "QUOTA_EXCEEDED"}`, string(encoded))
+
+		decoded, err := Decode(encoded)
+		require.NoError(t, err)
+		assert.ErrorIs(t, decoded, errQuotaExceeded)
+		assert.ErrorIs(t, decoded, ErrResourceExhausted)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		assert.PanicsWithValue(t, `errdetail: kind name "" has no letters or digits`, func() {
+			DefineKind("", ErrNotFound)
+		})
+		assert.PanicsWithValue(t, `errdetail: kind name " - " has no letters or digits`, func() {
+			DefineKind(" - ", ErrNotFound)
+		})
+		assert.PanicsWithValue(t, `errdetail: kind "not found" is already defined`, func() {
+			DefineKind("not found", "")
+		})
+		assert.PanicsWithValue(t, `errdetail: kind "Quota-Exceeded" is already defined`, func() {
+			DefineKind("Quota-Exceeded", "")
+		})
+		assert.PanicsWithValue(t, `errdetail: kind "Unknown" is already defined`, func() {
+			DefineKind("Unknown", "")
+		})
+		assert.PanicsWithValue(t, `errdetail: parent kind "dummy" of "dummy child" is unknown`, func() {
+			DefineKind("dummy child", "dummy")
+		})
+	})
+}
+
+func newQuotaError() error {
+	return Wrap(errQuotaExceeded, "")
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+// WithMessage is an option for Detail constructs that sets the ID of the
+// detail's message in localization bundles along with the arguments of the
+// message templates, and marks the detail as not empty. The arguments are
+// copied the same way as by WithMeta. See the i18n package for details.
+func WithMessage(id string, args Meta) Option {
+	return func(d *Detail) {
+		d.messageID = id
+		d.messageArgs = args.clone()
+
+		if d.messageID != "" || d.messageArgs != nil {
+			d.filled = true
+		}
+	}
+}
+
+// MessageID is a Detail localization message ID getter.
+func (d *Detail) MessageID() string {
+	return d.messageID
+}
+
+// MessageArgs is a Detail message template arguments getter.
+// It returns a copy, so the detail can not be modified by the caller.
+func (d *Detail) MessageArgs() Meta {
+	return d.messageArgs.clone()
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestWithMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id     string
+		args   Meta
+		filled bool
+	}{
+		"id_and_args": {
+			id:     "user.password_too_short",
+			args:   Meta{"count": 8},
+			filled: true,
+		},
+		"id_only": {
+			id:     "user.email_taken",
+			filled: true,
+		},
+		"empty": {},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			detail := NewDetail(WithMessage(tt.id, tt.args))
+
+			assert.Equal(t, tt.id, detail.MessageID())
+			assert.Equal(t, tt.args, detail.MessageArgs())
+			assert.Equal(t, tt.filled, len(ExtractDetails(New("dummy", detail))) == 1)
+		})
+	}
+
+	t.Run("copied", func(t *testing.T) {
+		t.Parallel()
+
+		args := Meta{"names": []string{"a"}}
+		detail := NewDetail(WithMessage("id", args))
+
+		args["names"].([]string)[0] = "b"
+		detail.MessageArgs()["names"].([]string)[0] = "c"
+
+		assert.Equal(t, Meta{"names": []string{"a"}}, detail.MessageArgs())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		detail := NewDetail(WithCode("password_too_short"), WithMessage("user.password_too_short", Meta{"count": 8}))
+
+		encoded, err := json.Marshal(detail)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"code": "password_too_short",
+			"message_id": "user.password_too_short",
+			"message_args": {"count": 8}
+		}`, string(encoded))
+
+		var decoded Detail
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, "user.password_too_short", decoded.MessageID())
+
+		count, ok := MetaValue[int](NewDetail(WithMeta(decoded.MessageArgs())), "count")
+		assert.True(t, ok)
+		assert.Equal(t, 8, count)
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+)
+
+// clone returns a deep copy of the Meta. Maps and slices are copied
+// recursively, other values are copied as is.
+func (m Meta) clone() Meta {
+	if m == nil {
+		return nil
+	}
+
+	cloned := make(Meta, len(m))
+	for key, value := range m {
+		cloned[key] = cloneValue(value)
+	}
+
+	return cloned
+}
+
+// cloneValue returns a deep copy of maps and slices, other values are returned as is.
+func cloneValue(value interface{}) interface{} {
+	original := reflect.ValueOf(value)
+
+	switch original.Kind() { //nolint:exhaustive // other kinds are copied by value
+	case reflect.Map:
+		if original.IsNil() {
+			return value
+		}
+
+		cloned := reflect.MakeMapWithSize(original.Type(), original.Len())
+		for iter := original.MapRange(); iter.Next(); {
+			cloned.SetMapIndex(iter.Key(), cloneElem(iter.Value()))
+		}
+
+		return cloned.Interface()
+	case reflect.Slice:
+		if original.IsNil() {
+			return value
+		}
+
+		cloned := reflect.MakeSlice(original.Type(), original.Len(), original.Len())
+		for i := 0; i < original.Len(); i++ {
+			cloned.Index(i).Set(cloneElem(original.Index(i)))
+		}
+
+		return cloned.Interface()
+	default:
+		return value
+	}
+}
+
+// cloneElem returns a deep copy of a map or a slice element.
+func cloneElem(elem reflect.Value) reflect.Value {
+	if elem.Kind() == reflect.Interface && elem.IsNil() {
+		return elem
+	}
+
+	return reflect.ValueOf(cloneValue(elem.Interface()))
+}
+
+// MetaValue returns the detail's Meta value stored under the key as a value
+// of type T. Numeric values are converted to the numeric type T if the value
+// fits it exactly, e.g. a float64 value produced by JSON decoding is returned
+// as an int if it has no fractional part. MetaValue reports false if there is
+// no such key, or the value can not be represented by T.
+func MetaValue[T any](d Detail, key string) (T, bool) {
+	var zero T
+
+	value, ok := d.meta[key]
+	if !ok {
+		return zero, false
+	}
+
+	if typed, ok := value.(T); ok {
+		return typed, true
+	}
+
+	converted, ok := convertNumber(value, reflect.TypeOf(zero))
+	if !ok {
+		return zero, false
+	}
+
+	return converted.Interface().(T), true //nolint:forcetypeassert // the type is checked by convertNumber
+}
+
+// convertNumber converts the numeric value into the numeric target type.
+// It reports false if either the value or the type is not numeric, or the
+// value can not be represented by the type exactly.
+func convertNumber(value interface{}, target reflect.Type) (reflect.Value, bool) {
+	if number, ok := value.(json.Number); ok {
+		value = numberValue(number)
+	}
+
+	source := reflect.ValueOf(value)
+	if target == nil || !source.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	result := reflect.New(target).Elem()
+
+	switch {
+	case isInt(target.Kind()):
+		i, ok := toInt(source)
+		if !ok || result.OverflowInt(i) {
+			return reflect.Value{}, false
+		}
+
+		result.SetInt(i)
+	case isUint(target.Kind()):
+		u, ok := toUint(source)
+		if !ok || result.OverflowUint(u) {
+			return reflect.Value{}, false
+		}
+
+		result.SetUint(u)
+	case isFloat(target.Kind()):
+		f, ok := toFloat(source)
+		if !ok || result.OverflowFloat(f) {
+			return reflect.Value{}, false
+		}
+
+		result.SetFloat(f)
+	default:
+		return reflect.Value{}, false
+	}
+
+	return result, true
+}
+
+// numberValue returns the JSON number as an int64 if it is an integer,
+// as a float64 otherwise. Invalid numbers are returned as is.
+func numberValue(number json.Number) interface{} {
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+
+	return number
+}
+
+func toInt(v reflect.Value) (int64, bool) {
+	switch {
+	case isInt(v.Kind()):
+		return v.Int(), true
+	case isUint(v.Kind()):
+		return int64(v.Uint()), v.Uint() <= math.MaxInt64
+	case isFloat(v.Kind()):
+		f := v.Float()
+
+		return int64(f), f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+func toUint(v reflect.Value) (uint64, bool) {
+	switch {
+	case isInt(v.Kind()):
+		return uint64(v.Int()), v.Int() >= 0
+	case isUint(v.Kind()):
+		return v.Uint(), true
+	case isFloat(v.Kind()):
+		f := v.Float()
+
+		return uint64(f), f == math.Trunc(f) && f >= 0 && f < math.MaxUint64
+	default:
+		return 0, false
+	}
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch {
+	case isInt(v.Kind()):
+		return float64(v.Int()), true
+	case isUint(v.Kind()):
+		return float64(v.Uint()), true
+	case isFloat(v.Kind()):
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func isInt(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUint(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
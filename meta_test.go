@@ -0,0 +1,119 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+type priority int
+
+func TestMetaValue(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithMeta(Meta{
+		"id":       "42",
+		"attempt":  float64(3),
+		"ratio":    0.5,
+		"negative": -1,
+		"huge":     uint64(math.MaxUint64),
+		"number":   json.Number("7"),
+		"tags":     []string{"a", "b"},
+	}))
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		got, ok := MetaValue[string](detail, "id")
+		assert.True(t, ok)
+		assert.Equal(t, "42", got)
+
+		_, ok = MetaValue[int](detail, "id")
+		assert.False(t, ok)
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		t.Parallel()
+
+		attempt, ok := MetaValue[int](detail, "attempt")
+		assert.True(t, ok)
+		assert.Equal(t, 3, attempt)
+
+		custom, ok := MetaValue[priority](detail, "attempt")
+		assert.True(t, ok)
+		assert.Equal(t, priority(3), custom)
+
+		ratio, ok := MetaValue[float32](detail, "ratio")
+		assert.True(t, ok)
+		assert.Equal(t, float32(0.5), ratio)
+
+		negative, ok := MetaValue[int8](detail, "negative")
+		assert.True(t, ok)
+		assert.Equal(t, int8(-1), negative)
+
+		number, ok := MetaValue[uint](detail, "number")
+		assert.True(t, ok)
+		assert.Equal(t, uint(7), number)
+	})
+
+	t.Run("inexact_numbers", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := MetaValue[int](detail, "ratio")
+		assert.False(t, ok)
+
+		_, ok = MetaValue[uint](detail, "negative")
+		assert.False(t, ok)
+
+		_, ok = MetaValue[int64](detail, "huge")
+		assert.False(t, ok)
+
+		_, ok = MetaValue[int8](detail, "huge")
+		assert.False(t, ok)
+	})
+
+	t.Run("other_types", func(t *testing.T) {
+		t.Parallel()
+
+		tags, ok := MetaValue[[]string](detail, "tags")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"a", "b"}, tags)
+
+		value, ok := MetaValue[interface{}](detail, "id")
+		assert.True(t, ok)
+		assert.Equal(t, "42", value)
+
+		_, ok = MetaValue[string](detail, "missing")
+		assert.False(t, ok)
+
+		_, ok = MetaValue[string](NewDetail(), "id")
+		assert.False(t, ok)
+	})
+
+	t.Run("json_round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := json.Marshal(NewDetail(WithMeta(Meta{"attempt": 3, "limit": uint16(100)})))
+		require.NoError(t, err)
+
+		var decoded Detail
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+		attempt, ok := MetaValue[int](decoded, "attempt")
+		assert.True(t, ok)
+		assert.Equal(t, 3, attempt)
+
+		limit, ok := MetaValue[uint16](decoded, "limit")
+		assert.True(t, ok)
+		assert.Equal(t, uint16(100), limit)
+	})
+}
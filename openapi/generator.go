@@ -0,0 +1,434 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+	"github.com/dnozdrin/errdetail/i18n"
+	"github.com/dnozdrin/errdetail/jsonapi"
+	"github.com/dnozdrin/errdetail/problem"
+)
+
+// Names of the generated component schemas.
+const (
+	DetailSchema        = "ErrorDetail"
+	ErrorResponseSchema = "ErrorResponse"
+	ErrorSchema         = "Error"
+	ProblemSchema       = "Problem"
+	JSONAPIErrorsSchema = "JSONAPIErrors"
+	JSONAPIErrorSchema  = "JSONAPIError"
+)
+
+// jsonContentType is the media type of the httperr responses.
+const jsonContentType = "application/json"
+
+// Generator builds OpenAPI components for the error renderers.
+// A Generator must be created by NewGenerator.
+type Generator struct {
+	mapper   *httperr.Mapper
+	typeBase string
+	kinds    func() []errdetail.Kind
+	entries  func() []errdetail.CatalogEntry
+	bundle   *i18n.Bundle
+}
+
+// Option is a function type for Generator settings' setters.
+type Option func(*Generator)
+
+// WithMapper is an option for Generator constructs that sets the Mapper
+// used by the described renderers.
+func WithMapper(mapper *httperr.Mapper) Option {
+	return func(g *Generator) {
+		g.mapper = mapper
+	}
+}
+
+// WithTypeBaseURI is an option for Generator constructs that sets the prefix
+// of the type URIs used by the described problem or jsonapi renderer.
+func WithTypeBaseURI(base string) Option {
+	return func(g *Generator) {
+		g.typeBase = base
+	}
+}
+
+// WithKinds is an option for Generator constructs that sets the kinds
+// responses are generated for. By default, all kinds returned by
+// errdetail.Kinds are described.
+func WithKinds(kinds ...errdetail.Kind) Option {
+	return func(g *Generator) {
+		g.kinds = func() []errdetail.Kind {
+			return kinds
+		}
+	}
+}
+
+// WithEntries is an option for Generator constructs that sets the catalog
+// entries the detail codes are taken from. By default, the entries of the
+// default catalog are used.
+func WithEntries(entries []errdetail.CatalogEntry) Option {
+	return func(g *Generator) {
+		g.entries = func() []errdetail.CatalogEntry {
+			return entries
+		}
+	}
+}
+
+// WithBundle is an option for Generator constructs that sets the bundle
+// the described httperr Mapper localizes responses by, see httperr.WithBundle.
+// The titles of the HTTP responses are restricted to the kinds' names in all
+// of the bundle's languages. By default, responses are not localized.
+func WithBundle(bundle *i18n.Bundle) Option {
+	return func(g *Generator) {
+		g.bundle = bundle
+	}
+}
+
+// NewGenerator represents a Generator constructor. By default, the Generator
+// describes the renderers created with their default options.
+func NewGenerator(opts ...Option) *Generator {
+	generator := &Generator{
+		mapper:   httperr.NewMapper(),
+		typeBase: problem.DefaultTypeBaseURI,
+		kinds:    errdetail.Kinds,
+		entries:  errdetail.CatalogEntries,
+	}
+
+	for i := range opts {
+		opts[i](generator)
+	}
+
+	return generator
+}
+
+// defaultGenerator is used by the package level functions.
+var defaultGenerator = NewGenerator() //nolint:gochecknoglobals // immutable after creation
+
+// HTTP describes the responses of the httperr package using the default Generator.
+func HTTP() Components {
+	return defaultGenerator.HTTP()
+}
+
+// Problem describes the responses of the problem package using the default Generator.
+func Problem() Components {
+	return defaultGenerator.Problem()
+}
+
+// JSONAPI describes the responses of the jsonapi package using the default Generator.
+func JSONAPI() Components {
+	return defaultGenerator.JSONAPI()
+}
+
+// HTTP describes the responses written by httperr.Mapper.WriteError.
+// There is a response per each kind and one for errors of unknown kind,
+// which restricts the status, the title and the code to the kind's ones.
+func (g *Generator) HTTP() Components {
+	var statuses, titles, codes []interface{}
+
+	responses := make(map[string]*Response)
+
+	for _, kind := range g.allKinds() {
+		resolved, mapping := g.mapper.Map(kind)
+
+		kindTitles := g.titles(resolved)
+
+		statuses = appendUnique(statuses, mapping.Status)
+		titles = appendUnique(titles, kindTitles...)
+		codes = appendUnique(codes, mapping.Code)
+
+		responses[kind.Code()] = response(kind, jsonContentType, ErrorResponseSchema, object(map[string]*Schema{
+			"error": object(map[string]*Schema{
+				"status": {Enum: []interface{}{mapping.Status}},
+				"title":  {Enum: kindTitles},
+				"code":   {Enum: []interface{}{mapping.Code}},
+			}),
+		}))
+	}
+
+	return Components{
+		Schemas: map[string]*Schema{
+			ErrorResponseSchema: object(map[string]*Schema{"error": Ref(ErrorSchema)}, "error"),
+			ErrorSchema: object(map[string]*Schema{
+				"status":   withEnum(&Schema{Type: "integer", Description: "The HTTP status code."}, sortStatuses(statuses)),
+				"title":    withEnum(stringValue("The name of the error's kind."), titles),
+				"code":     withEnum(stringValue("The public code of the error's kind."), codes),
+				"severity": withEnum(stringValue("The error's severity, rendered if enabled."), severities()),
+				"details":  arrayOf(Ref(DetailSchema), "The error's details."),
+			}, "status", "title", "code"),
+			DetailSchema: g.detail(),
+		},
+		Responses: responses,
+	}
+}
+
+// Problem describes the Problem Details documents written by
+// problem.Renderer.WriteError. There is a response per each kind and
+// one for errors of unknown kind, which restricts the type, the title
+// and the status to the kind's ones.
+func (g *Generator) Problem() Components {
+	var types, titles, statuses []interface{}
+
+	responses := make(map[string]*Response)
+
+	for _, kind := range g.allKinds() {
+		_, mapping := g.mapper.Map(kind)
+		typeURI := g.problemType(kind)
+		title := problemTitle(kind, mapping.Status)
+
+		types = appendUnique(types, typeURI)
+		titles = appendUnique(titles, title)
+		statuses = appendUnique(statuses, mapping.Status)
+
+		responses[kind.Code()] = response(kind, problem.ContentType, ProblemSchema, object(map[string]*Schema{
+			"type":   {Enum: []interface{}{typeURI}},
+			"title":  {Enum: []interface{}{title}},
+			"status": {Enum: []interface{}{mapping.Status}},
+		}))
+	}
+
+	document := object(map[string]*Schema{
+		"type": withEnum(&Schema{
+			Type:        "string",
+			Format:      "uri-reference",
+			Description: "The URI that identifies the error's kind.",
+		}, types),
+		"title":    withEnum(stringValue("The name of the error's kind."), titles),
+		"status":   withEnum(&Schema{Type: "integer", Description: "The HTTP status code."}, sortStatuses(statuses)),
+		"detail":   stringValue("The error message."),
+		"instance": stringValue("The request URI."),
+		"errors":   arrayOf(Ref(DetailSchema), "The error's details."),
+		"severity": withEnum(stringValue("The error's severity, rendered if enabled."), severities()),
+	}, "type", "title", "status")
+	document.Description = "The details' Meta entries are added as extension members."
+	document.AdditionalProperties = anyValue()
+
+	return Components{
+		Schemas: map[string]*Schema{
+			ProblemSchema: document,
+			DetailSchema:  g.detail(),
+		},
+		Responses: responses,
+	}
+}
+
+// JSONAPI describes the JSON:API documents written by
+// jsonapi.Renderer.WriteError. There is a response per each kind and
+// one for errors of unknown kind, which restricts the status and the
+// type link of the error objects to the kind's ones.
+func (g *Generator) JSONAPI() Components {
+	var types, statuses, codes []interface{}
+
+	responses := make(map[string]*Response)
+
+	for _, kind := range g.allKinds() {
+		_, mapping := g.mapper.Map(kind)
+		status := strconv.Itoa(mapping.Status)
+
+		statuses = appendUnique(statuses, status)
+
+		constraint := map[string]*Schema{"status": {Enum: []interface{}{status}}}
+
+		if typeURI := g.jsonapiType(kind); typeURI != "" {
+			types = appendUnique(types, typeURI)
+			constraint["links"] = object(map[string]*Schema{"type": {Enum: []interface{}{typeURI}}})
+		}
+
+		responses[kind.Code()] = response(kind, jsonapi.ContentType, JSONAPIErrorsSchema, object(map[string]*Schema{
+			"errors": arrayOf(object(constraint), ""),
+		}))
+	}
+
+	if entries := g.entries(); len(entries) != 0 {
+		for _, kind := range g.allKinds() {
+			codes = appendUnique(codes, kind.Code())
+		}
+
+		for _, code := range catalogCodes(entries) {
+			codes = appendUnique(codes, code)
+		}
+	}
+
+	return Components{
+		Schemas: map[string]*Schema{
+			JSONAPIErrorsSchema: object(map[string]*Schema{
+				"errors": arrayOf(Ref(JSONAPIErrorSchema), "An error object per each of the error's details."),
+				"meta": object(map[string]*Schema{
+					"message":  stringValue("The error message."),
+					"severity": withEnum(stringValue("The error's severity, rendered if enabled."), severities()),
+				}),
+			}, "errors"),
+			JSONAPIErrorSchema: object(map[string]*Schema{
+				"id": stringValue("The identifier of the occurrence."),
+				"links": object(map[string]*Schema{
+					"about": {Type: "string", Format: "uri", Description: "The documentation of the detail's code."},
+					"type": withEnum(&Schema{
+						Type:        "string",
+						Format:      "uri-reference",
+						Description: "The URI that identifies the error's kind.",
+					}, types),
+				}),
+				"status": withEnum(stringValue("The HTTP status code."), sortStatuses(statuses)),
+				"code":   withEnum(stringValue("The detail's code, or the kind's code for errors without details."), codes),
+				"title":  stringValue("The detail's description, or the kind's name for errors without details."),
+				"detail": stringValue("The detail's reason."),
+				"source": object(map[string]*Schema{
+					"pointer":   stringValue("The JSON Pointer to the field the detail refers to."),
+					"parameter": stringValue("The query parameter the detail refers to."),
+					"header":    stringValue("The header the detail refers to."),
+				}),
+				"meta": freeForm("The detail's Meta and domain."),
+			}, "status"),
+		},
+		Responses: responses,
+	}
+}
+
+// detail describes httperr.PublicDetail, the encoding of details in the
+// responses. The codes are restricted to the ones of the catalog entries, if any.
+func (g *Generator) detail() *Schema {
+	return object(map[string]*Schema{
+		"domain":      stringValue("The domain the code belongs to."),
+		"code":        withEnum(stringValue("The detail's code."), catalogCodes(g.entries())),
+		"description": stringValue("The human-readable description of the code."),
+		"field":       stringValue("The request field the detail refers to."),
+		"reason":      stringValue("The human-readable explanation of this occurrence."),
+		"help_url":    {Type: "string", Format: "uri", Description: "The documentation of the code."},
+		"meta":        freeForm("The additional information about the occurrence."),
+		"payload":     anyValue(),
+		"visibility":  withEnum(stringValue("The visibility of internal details."), []interface{}{"internal"}),
+		"severity":    withEnum(stringValue("The detail's severity."), severities()),
+	})
+}
+
+// severities returns the names of the severities.
+func severities() []interface{} {
+	values := make([]interface{}, 0, len(errdetail.Severities()))
+	for _, severity := range errdetail.Severities() {
+		values = append(values, severity.String())
+	}
+
+	return values
+}
+
+// titles returns the name of the kind followed by its localized names
+// in the bundle's languages, if any.
+func (g *Generator) titles(kind errdetail.Kind) []interface{} {
+	titles := []interface{}{kind.String()}
+
+	if g.bundle != nil {
+		for _, lang := range g.bundle.Languages() {
+			titles = appendUnique(titles, g.bundle.LocalizeKind(kind, lang))
+		}
+	}
+
+	return titles
+}
+
+// allKinds returns the described kinds followed by the zero Kind.
+func (g *Generator) allKinds() []errdetail.Kind {
+	kinds := g.kinds()
+
+	return append(kinds[:len(kinds):len(kinds)], "")
+}
+
+// problemType returns the problem type URI of the kind, as problem.Renderer does.
+func (g *Generator) problemType(kind errdetail.Kind) string {
+	if kind == "" {
+		return "about:blank"
+	}
+
+	return g.typeBase + typeName(kind)
+}
+
+// problemTitle returns the title of the problem of the kind, as problem.Renderer
+// does: the status phrase for errors without a kind.
+func problemTitle(kind errdetail.Kind, status int) string {
+	if kind == "" {
+		return http.StatusText(status)
+	}
+
+	return kind.String()
+}
+
+// jsonapiType returns the type link of the kind, as jsonapi.Renderer does.
+func (g *Generator) jsonapiType(kind errdetail.Kind) string {
+	if kind == "" || g.typeBase == "" {
+		return ""
+	}
+
+	return g.typeBase + typeName(kind)
+}
+
+func typeName(kind errdetail.Kind) string {
+	return strings.ToLower(strings.ReplaceAll(kind.Code(), "_", "-"))
+}
+
+// response returns the response of the kind, its schema is the schema with
+// the given name narrowed by the constraint.
+func response(kind errdetail.Kind, contentType, name string, constraint *Schema) *Response {
+	return &Response{
+		Description: kind.String() + " error",
+		Content: map[string]MediaType{
+			contentType: {Schema: &Schema{AllOf: []*Schema{Ref(name), constraint}}},
+		},
+	}
+}
+
+func catalogCodes(entries []errdetail.CatalogEntry) []interface{} {
+	var codes []interface{}
+
+	for _, entry := range entries {
+		codes = appendUnique(codes, entry.Code)
+	}
+
+	return codes
+}
+
+// appendUnique appends the values that are not in the slice yet.
+func appendUnique(values []interface{}, added ...interface{}) []interface{} {
+	for _, value := range added {
+		if !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortStatuses sorts the HTTP status codes, either integers or strings, in ascending order.
+func sortStatuses(statuses []interface{}) []interface{} {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statusNumber(statuses[i]) < statusNumber(statuses[j])
+	})
+
+	return statuses
+}
+
+func statusNumber(status interface{}) int {
+	if s, ok := status.(string); ok {
+		n, _ := strconv.Atoi(s)
+
+		return n
+	}
+
+	n, _ := status.(int)
+
+	return n
+}
@@ -0,0 +1,307 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+	"github.com/dnozdrin/errdetail/i18n"
+	"github.com/dnozdrin/errdetail/jsonapi"
+	"github.com/dnozdrin/errdetail/problem"
+
+	. "github.com/dnozdrin/errdetail/openapi"
+)
+
+//nolint:gochecknoglobals // flags are defined on a package initialization
+var update = flag.Bool("update", false, "update golden files")
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var errOrderLocked = errdetail.DefineKind("openapi order locked", errdetail.ErrAborted)
+
+func entries() []errdetail.CatalogEntry {
+	return []errdetail.CatalogEntry{
+		{Code: "email_taken", Domain: "user", Kind: errdetail.ErrAlreadyExists},
+		{Code: "order_locked", Domain: "order", Kind: errOrderLocked},
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := NewGenerator(
+		WithKinds(errdetail.ErrNotFound, errOrderLocked),
+		WithEntries(entries()),
+		WithMapper(httperr.NewMapper(
+			httperr.WithFallback(httperr.Mapping{Status: http.StatusServiceUnavailable, Code: "UNEXPECTED"}),
+		)),
+	)
+
+	tests := map[string]struct {
+		components Components
+		golden     string
+	}{
+		"http": {
+			components: generator.HTTP(),
+			golden:     "testdata/http.json",
+		},
+		"problem": {
+			components: generator.Problem(),
+			golden:     "testdata/problem.json",
+		},
+		"jsonapi": {
+			components: generator.JSONAPI(),
+			golden:     "testdata/jsonapi.json",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := json.MarshalIndent(tt.components, "", "  ")
+			require.NoError(t, err)
+
+			if *update {
+				require.NoError(t, os.WriteFile(tt.golden, append(actual, '\n'), 0o600))
+			}
+
+			expected, err := os.ReadFile(tt.golden)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestGenerator_noEntries(t *testing.T) {
+	t.Parallel()
+
+	generator := NewGenerator(WithKinds(errdetail.ErrNotFound), WithEntries(nil), WithTypeBaseURI(""))
+
+	components := generator.JSONAPI()
+	assert.Empty(t, components.Schemas[JSONAPIErrorSchema].Properties["code"].Enum)
+	assert.Empty(t, components.Schemas[JSONAPIErrorSchema].Properties["links"].Properties["type"].Enum)
+	assert.NotContains(t, components.Responses["NOT_FOUND"].Content[jsonapi.ContentType].Schema.AllOf[1].
+		Properties["errors"].Items.Properties, "links")
+
+	assert.Empty(t, generator.HTTP().Schemas[DetailSchema].Properties["code"].Enum)
+}
+
+// TestGenerator_conformance checks that the responses written by the renderers
+// for every kind match the generated responses of the kind.
+func TestGenerator_conformance(t *testing.T) {
+	t.Parallel()
+
+	mappings := []httperr.MapperOption{httperr.WithMapping(errdetail.ErrNotFound, httperr.Mapping{
+		Status: http.StatusGone,
+		Code:   "GONE",
+	})}
+	mapper := httperr.NewMapper(mappings...)
+	generator := NewGenerator(WithMapper(mapper), WithEntries(entries()))
+
+	bundle := i18n.NewBundle()
+	require.NoError(t, bundle.AddMessages("uk", map[string]i18n.Message{
+		i18n.KindMessageID(errdetail.ErrNotFound): {Description: i18n.Text{Other: "не знайдено"}},
+		i18n.KindMessageID(""):                    {Description: i18n.Text{Other: "невідома помилка"}},
+	}))
+
+	localizedMapper := httperr.NewMapper(append(mappings, httperr.WithBundle(bundle))...)
+	localizedGenerator := NewGenerator(WithMapper(localizedMapper), WithEntries(entries()), WithBundle(bundle))
+
+	tests := map[string]struct {
+		components  Components
+		write       func(http.ResponseWriter, *http.Request, error)
+		contentType string
+		lang        string
+	}{
+		"http": {
+			components:  generator.HTTP(),
+			write:       httperr.NewMapper(append(mappings, httperr.WithSeverity())...).WriteError,
+			contentType: "application/json",
+		},
+		"http_localized": {
+			components:  localizedGenerator.HTTP(),
+			write:       localizedMapper.WriteError,
+			contentType: "application/json",
+			lang:        "uk",
+		},
+		"problem": {
+			components:  generator.Problem(),
+			write:       problem.NewRenderer(problem.WithMapper(mapper), problem.WithSeverity()).WriteError,
+			contentType: problem.ContentType,
+		},
+		"jsonapi": {
+			components:  generator.JSONAPI(),
+			write:       jsonapi.NewRenderer(jsonapi.WithMapper(mapper), jsonapi.WithSeverity()).WriteError,
+			contentType: jsonapi.ContentType,
+		},
+	}
+
+	details := map[string][]errdetail.Detail{
+		"no details": nil,
+		"details": {
+			errdetail.NewDetail(
+				errdetail.FromCatalog("email_taken"),
+				errdetail.WithField("user.email"),
+				errdetail.WithReason("email is used"),
+				errdetail.WithHelpURL("https://example.com/errors#email-taken"),
+				errdetail.WithMeta(errdetail.Meta{"email": "user@example.com"}),
+			),
+			errdetail.NewDetail(
+				errdetail.WithCode("order_locked"),
+				errdetail.WithPayload([]int{1, 2}),
+				errdetail.WithSeverity(errdetail.SeverityWarning),
+			),
+			errdetail.NewDetail(
+				errdetail.WithReasonTemplate("order {{.id}} is locked", errdetail.Meta{"id": 42}),
+				errdetail.WithMessage("order.locked", errdetail.Meta{"count": 1}),
+			),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, kind := range append(errdetail.Kinds(), "") {
+				for detailsName, details := range details {
+					request := httptest.NewRequest(http.MethodGet, "/users", nil)
+					if tt.lang != "" {
+						request.Header.Set("Accept-Language", tt.lang)
+					}
+
+					recorder := httptest.NewRecorder()
+					tt.write(recorder, request, errdetail.Restore("request failed", kind, details...))
+
+					var body interface{}
+					require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+					response := tt.components.Responses[kind.Code()]
+					require.NotNil(t, response, kind.Code())
+
+					schema := response.Content[tt.contentType].Schema
+					require.NotNil(t, schema, kind.Code())
+
+					assert.NoError(t, validate(tt.components, schema, body, "", false),
+						"%s, %s: %s", kind.Code(), detailsName, recorder.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// validate checks the value against the subset of the schema keywords
+// supported by the generator. The component schemas are checked strictly,
+// members they do not describe are not allowed.
+func validate(components Components, schema *Schema, value interface{}, path string, strict bool) error {
+	if schema.Ref != "" {
+		return validate(components, components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")],
+			value, path, true)
+	}
+
+	for _, part := range schema.AllOf {
+		if err := validate(components, part, value, path, false); err != nil {
+			return err
+		}
+	}
+
+	if len(schema.Enum) != 0 && !inEnum(schema.Enum, value) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, schema.Enum)
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: %v is not a string", path, value)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int(n)) {
+			return fmt.Errorf("%s: %v is not an integer", path, value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, value)
+		}
+
+		for i, item := range items {
+			if err := validate(components, schema.Items, item, fmt.Sprintf("%s/%d", path, i), strict); err != nil {
+				return err
+			}
+		}
+	case "object":
+		return validateObject(components, schema, value, path, strict)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, schema.Type)
+	}
+
+	return nil
+}
+
+func validateObject(components Components, schema *Schema, value interface{}, path string, strict bool) error {
+	members, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: %v is not an object", path, value)
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := members[name]; !ok {
+			return fmt.Errorf("%s: %s is required", path, name)
+		}
+	}
+
+	for name, member := range members {
+		property, ok := schema.Properties[name]
+		if !ok {
+			property = schema.AdditionalProperties
+		}
+
+		if property == nil && strict {
+			return fmt.Errorf("%s: %s is not expected", path, name)
+		}
+
+		if property == nil {
+			continue
+		}
+
+		if err := validate(components, property, member, path+"/"+name, strict); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, v := range enum {
+		if n, ok := v.(int); ok {
+			v = float64(n)
+		}
+
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package openapi generates OpenAPI 3 components that describe the error
+// responses written by the httperr, problem and jsonapi renderers.
+//
+// The components are built from the same Mapper, kinds and catalog the
+// renderers use, so API specifications stay in sync with the responses.
+// They can be encoded into JSON or YAML and referred to from the paths
+// of a specification, e.g. by $ref: errors.yaml#/components/responses/NOT_FOUND.
+package openapi
+
+// Components holds the reusable objects of an OpenAPI document.
+type Components struct {
+	// Schemas are the response body schemas keyed by their names.
+	Schemas map[string]*Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	// Responses are the error responses keyed by the codes of the kinds they stand for.
+	Responses map[string]*Response `json:"responses,omitempty" yaml:"responses,omitempty"`
+}
+
+// Schema represents an OpenAPI Schema Object. Only the keywords used
+// to describe error responses are supported.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+}
+
+// Response represents an OpenAPI Response Object.
+type Response struct {
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType represents an OpenAPI Media Type Object.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Ref returns a schema that refers to the component schema with the given name.
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// anyValue is the schema of values of any type.
+func anyValue() *Schema {
+	return &Schema{}
+}
+
+func stringValue(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+func object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// freeForm is the schema of objects with arbitrary members.
+func freeForm(description string) *Schema {
+	return &Schema{Type: "object", Description: description, AdditionalProperties: anyValue()}
+}
+
+func arrayOf(items *Schema, description string) *Schema {
+	return &Schema{Type: "array", Description: description, Items: items}
+}
+
+// withEnum sets the schema's enum if there are values.
+func withEnum(schema *Schema, values []interface{}) *Schema {
+	if len(values) != 0 {
+		schema.Enum = values
+	}
+
+	return schema
+}
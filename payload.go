@@ -0,0 +1,41 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"encoding/json"
+)
+
+// WithPayload is an option for Detail constructs that attaches a typed
+// payload and marks the detail as not empty. The payload is encoded to JSON
+// along with the detail and can be read back by Payload, both before and
+// after decoding. A detail holds a single payload, the last one wins.
+func WithPayload[T any](payload T) Option {
+	return func(d *Detail) {
+		d.payload = payload
+		d.filled = true
+	}
+}
+
+// Payload returns the detail's payload as a value of type T. A payload
+// restored from JSON is decoded into T. Payload reports false if the detail
+// has no payload, or the payload can not be represented by T.
+func Payload[T any](d Detail) (T, bool) {
+	var zero T
+
+	switch payload := d.payload.(type) {
+	case T:
+		return payload, true
+	case json.RawMessage:
+		var decoded T
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return zero, false
+		}
+
+		return decoded, true
+	default:
+		return zero, false
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+type quotaViolation struct {
+	Subject string `json:"subject"`
+	Limit   int    `json:"limit"`
+}
+
+func TestPayload(t *testing.T) {
+	t.Parallel()
+
+	violation := quotaViolation{Subject: "user:42", Limit: 100}
+
+	t.Run("typed", func(t *testing.T) {
+		t.Parallel()
+
+		detail := NewDetail(WithPayload(violation))
+
+		got, ok := Payload[quotaViolation](detail)
+		assert.True(t, ok)
+		assert.Equal(t, violation, got)
+
+		_, ok = Payload[*quotaViolation](detail)
+		assert.False(t, ok)
+
+		_, ok = Payload[string](detail)
+		assert.False(t, ok)
+	})
+
+	t.Run("no_payload", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := Payload[quotaViolation](NewDetail(WithCode("dummy_code")))
+		assert.False(t, ok)
+
+		_, ok = Payload[interface{}](NewDetail())
+		assert.False(t, ok)
+	})
+
+	t.Run("json_round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewResourceExhausted("quota exceeded", NewDetail(WithCode("quota"), WithPayload(violation)))
+
+		encoded, marshalErr := json.Marshal(err)
+		require.NoError(t, marshalErr)
+		assert.JSONEq(t, `{
+			"message": "quota exceeded: resource exhausted",
+			"kind": "RESOURCE_EXHAUSTED",
+			"details": [{"code": "quota", "payload": {"subject": "user:42", "limit": 100}}]
+		}`, string(encoded))
+
+		decoded, decodeErr := Decode(encoded)
+		require.NoError(t, decodeErr)
+
+		details := ExtractDetails(decoded)
+		require.Len(t, details, 1)
+
+		got, ok := Payload[quotaViolation](details[0])
+		assert.True(t, ok)
+		assert.Equal(t, violation, got)
+
+		_, ok = Payload[[]string](details[0])
+		assert.False(t, ok)
+	})
+
+	t.Run("unsupported_payload", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := json.Marshal(NewDetail(WithPayload(make(chan int))))
+		assert.Error(t, err)
+	})
+}
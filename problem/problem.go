@@ -0,0 +1,125 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package problem renders detailed errors as Problem Details documents
+// described by RFC 9457 and parses such documents back into errors.
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+)
+
+// ContentType is the media type of Problem Details documents.
+const ContentType = "application/problem+json"
+
+// Problem represents a Problem Details document.
+type Problem struct {
+	// Type is a URI reference that identifies the problem type.
+	Type string
+	// Title is a short, human-readable summary of the problem type.
+	Title string
+	// Status is the HTTP status code generated by the origin server.
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference that identifies the specific occurrence of the problem.
+	Instance string
+	// Errors is the "errors" extension member that holds the error's details.
+	// They are encoded as httperr.PublicDetail.
+	Errors []errdetail.Detail
+	// Extensions are the other extension members.
+	Extensions map[string]interface{}
+}
+
+// members returns the names of the members that are not extensions.
+func members() []string {
+	return []string{"type", "title", "status", "detail", "instance", "errors"}
+}
+
+// MarshalJSON is the `json.Marshaler` interface implementation for Problem.
+// Extensions are encoded as members of the document, unless their names
+// are taken by the standard members. Empty members are omitted.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	document := make(map[string]interface{}, len(p.Extensions)+len(members()))
+	for name, value := range p.Extensions {
+		document[name] = value
+	}
+
+	for _, name := range members() {
+		delete(document, name)
+	}
+
+	if p.Type != "" {
+		document["type"] = p.Type
+	}
+
+	if p.Title != "" {
+		document["title"] = p.Title
+	}
+
+	if p.Status != 0 {
+		document["status"] = p.Status
+	}
+
+	if p.Detail != "" {
+		document["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		document["instance"] = p.Instance
+	}
+
+	if len(p.Errors) != 0 {
+		document["errors"] = httperr.NewPublicDetails(p.Errors)
+	}
+
+	return json.Marshal(document)
+}
+
+// UnmarshalJSON is the `json.Unmarshaler` interface implementation for Problem.
+// Members that are not defined by RFC 9457 and are not the "errors" member
+// are decoded into Extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var standard struct {
+		Type     string             `json:"type"`
+		Title    string             `json:"title"`
+		Status   int                `json:"status"`
+		Detail   string             `json:"detail"`
+		Instance string             `json:"instance"`
+		Errors   []errdetail.Detail `json:"errors"`
+	}
+
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return fmt.Errorf("decode problem: %w", err)
+	}
+
+	var extensions map[string]interface{}
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		return fmt.Errorf("decode problem extensions: %w", err)
+	}
+
+	for _, name := range members() {
+		delete(extensions, name)
+	}
+
+	if len(extensions) == 0 {
+		extensions = nil
+	}
+
+	*p = Problem{
+		Type:       standard.Type,
+		Title:      standard.Title,
+		Status:     standard.Status,
+		Detail:     standard.Detail,
+		Instance:   standard.Instance,
+		Errors:     standard.Errors,
+		Extensions: extensions,
+	}
+
+	return nil
+}
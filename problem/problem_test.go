@@ -0,0 +1,103 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package problem_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+
+	. "github.com/dnozdrin/errdetail/problem"
+)
+
+func TestProblemJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		problem Problem
+		json    string
+	}{
+		"full": {
+			problem: Problem{
+				Type:     "https://example.com/problems/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   403,
+				Detail:   "Your current balance is 30, but that costs 50.",
+				Instance: "/account/12345/msgs/abc",
+				Errors: []errdetail.Detail{
+					errdetail.NewDetail(errdetail.WithCode("out_of_credit")),
+				},
+				Extensions: map[string]interface{}{
+					"balance":  float64(30),
+					"accounts": []interface{}{"/account/12345", "/account/67890"},
+				},
+			},
+			json: `{
+				"type": "https://example.com/problems/out-of-credit",
+				"title": "You do not have enough credit.",
+				"status": 403,
+				"detail": "Your current balance is 30, but that costs 50.",
+				"instance": "/account/12345/msgs/abc",
+				"errors": [{"code": "out_of_credit"}],
+				"balance": 30,
+				"accounts": ["/account/12345", "/account/67890"]
+			}`,
+		},
+		"empty": {
+			problem: Problem{},
+			json:    `{}`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(tt.problem)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(encoded))
+
+			var decoded Problem
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+			assert.Equal(t, tt.problem, decoded)
+		})
+	}
+
+	t.Run("templates", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := json.Marshal(Problem{Errors: []errdetail.Detail{errdetail.NewDetail(
+			errdetail.WithDescriptionTemplate("email {{.email}} is taken", errdetail.Meta{"email": "a@b.c"}),
+			errdetail.WithSeverity(errdetail.SeverityInfo),
+		)}})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors": [{"description": "email a@b.c is taken", "severity": "info"}]}`, string(encoded))
+	})
+
+	t.Run("reserved_extensions", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := json.Marshal(Problem{
+			Title:      "dummy title",
+			Extensions: map[string]interface{}{"title": "dummy extension", "status": 200},
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title": "dummy title"}`, string(encoded))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var decoded Problem
+		assert.Error(t, json.Unmarshal([]byte(`{"status": "404"}`), &decoded))
+		assert.Error(t, json.Unmarshal([]byte(`[]`), &decoded))
+	})
+}
@@ -0,0 +1,235 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+)
+
+const (
+	// DefaultTypeBaseURI is the default prefix of problem type URIs.
+	DefaultTypeBaseURI = "urn:errdetail:kind:"
+	// blankType is the problem type URI of problems with no additional semantics.
+	blankType = "about:blank"
+)
+
+// Renderer converts errors into Problem Details documents and back.
+// A Renderer must be created by NewRenderer.
+type Renderer struct {
+	mapper    *httperr.Mapper
+	typeBase  string
+	internals bool
+	severity  bool
+}
+
+// Option is a function type for Renderer settings' setters.
+type Option func(*Renderer)
+
+// WithMapper is an option for Renderer constructs that sets the Mapper
+// used to determine the problem status.
+func WithMapper(mapper *httperr.Mapper) Option {
+	return func(r *Renderer) {
+		r.mapper = mapper
+	}
+}
+
+// WithTypeBaseURI is an option for Renderer constructs that sets the prefix
+// of problem type URIs. A type URI is the prefix followed by the error kind's
+// code in lower case, with underscores replaced by hyphens, e.g. not-found.
+func WithTypeBaseURI(base string) Option {
+	return func(r *Renderer) {
+		r.typeBase = base
+	}
+}
+
+// WithInternals is an option for Renderer constructs that disables rendering
+// of the public view of errors, so the full error messages and internal details
+// are rendered. It is meant for development environments only. By default,
+// problems are built from errdetail.PublicView.
+func WithInternals() Option {
+	return func(r *Renderer) {
+		r.internals = true
+	}
+}
+
+// WithSeverity is an option for Renderer constructs that enables rendering
+// of the error's severity, see errdetail.SeverityOf, as the "severity"
+// extension member. By default, it is not rendered.
+func WithSeverity() Option {
+	return func(r *Renderer) {
+		r.severity = true
+	}
+}
+
+// NewRenderer represents a Renderer constructor. By default, the Renderer
+// uses the httperr default mappings and DefaultTypeBaseURI.
+func NewRenderer(opts ...Option) *Renderer {
+	renderer := &Renderer{
+		mapper:   httperr.NewMapper(),
+		typeBase: DefaultTypeBaseURI,
+	}
+
+	for i := range opts {
+		opts[i](renderer)
+	}
+
+	return renderer
+}
+
+// defaultRenderer is used by the package level functions.
+var defaultRenderer = NewRenderer() //nolint:gochecknoglobals // immutable after creation
+
+// New converts the error into a Problem using the default Renderer.
+func New(err error) *Problem {
+	return defaultRenderer.New(err)
+}
+
+// WriteError writes the error as a Problem Details document using the default Renderer.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	defaultRenderer.WriteError(w, r, err)
+}
+
+// Parse decodes a Problem Details document into an error using the default Renderer.
+func Parse(data []byte) (error, error) {
+	return defaultRenderer.Parse(data)
+}
+
+// New converts the error into a Problem. The type, the title and the status
+// are determined by the error's kind, the detail is the error message. Errors
+// without a kind are of the about:blank type, titled by the status phrase.
+// Unless WithInternals is set, the public view of the error is converted,
+// see errdetail.PublicView.
+// The status of a custom kind is determined by its nearest mapped ancestor.
+// The error's details are put into the "errors" extension member, and their
+// Meta entries are put into the other extension members, the earlier details
+// take precedence. If WithSeverity is set, the error's severity takes
+// precedence over them as the "severity" member. The details are redacted
+// by errdetail.RedactDetails, and the error message by errdetail.RedactText.
+// Returns nil if the error is nil.
+func (r *Renderer) New(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	if !r.internals {
+		err = errdetail.PublicView(err)
+	}
+
+	kind := errdetail.KindOf(err)
+	_, mapping := r.mapper.Map(err)
+
+	problem := &Problem{
+		Type:   r.typeOf(kind),
+		Title:  titleOf(kind, mapping.Status),
+		Status: mapping.Status,
+		Detail: errdetail.RedactText(err.Error()),
+		Errors: errdetail.RedactDetails(errdetail.ExtractDetails(err)),
+	}
+
+	if r.severity {
+		problem.Extensions = map[string]interface{}{"severity": errdetail.SeverityOf(err).String()}
+	}
+
+	for i := range problem.Errors {
+		for name, value := range problem.Errors[i].Meta() {
+			if problem.Extensions == nil {
+				problem.Extensions = make(map[string]interface{})
+			}
+
+			if _, ok := problem.Extensions[name]; !ok {
+				problem.Extensions[name] = value
+			}
+		}
+	}
+
+	return problem
+}
+
+// WriteError writes the error as a Problem Details document with the status
+// code the error is mapped to. The problem instance is the request URI.
+// The body is omitted for HEAD requests. Nothing is written if the error is nil.
+func (r *Renderer) WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	problem := r.New(err)
+	if problem == nil {
+		return
+	}
+
+	problem.Instance = req.URL.RequestURI()
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// Error converts the Problem back into an error. The error's kind is
+// determined by the problem type, or by the status if the type is unknown.
+// The error message is the problem detail, or the title if the detail is
+// empty, and the error's details are taken from the "errors" member.
+// Returns nil if the Problem is nil.
+func (r *Renderer) Error(problem *Problem) error {
+	if problem == nil {
+		return nil
+	}
+
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+
+	return errdetail.Restore(message, r.kindOf(problem), problem.Errors...)
+}
+
+// Parse decodes a Problem Details document and converts it into an error.
+func (r *Renderer) Parse(data []byte) (error, error) {
+	var problem *Problem
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return nil, fmt.Errorf("parse problem: %w", err)
+	}
+
+	return r.Error(problem), nil
+}
+
+// typeOf returns the problem type URI of the kind.
+func (r *Renderer) typeOf(kind errdetail.Kind) string {
+	if kind == "" {
+		return blankType
+	}
+
+	return r.typeBase + strings.ToLower(strings.ReplaceAll(kind.Code(), "_", "-"))
+}
+
+// titleOf returns the title of the problem of the kind. Problems of errors
+// without a kind are of the about:blank type, so their title is the status
+// phrase, as RFC 9457 recommends.
+func titleOf(kind errdetail.Kind, status int) string {
+	if kind == "" {
+		return http.StatusText(status)
+	}
+
+	return kind.String()
+}
+
+// kindOf returns the kind of the problem.
+func (r *Renderer) kindOf(problem *Problem) errdetail.Kind {
+	for _, kind := range errdetail.Kinds() {
+		if r.typeOf(kind) == problem.Type {
+			return kind
+		}
+	}
+
+	return httperr.KindOfStatus(problem.Status)
+}
@@ -0,0 +1,344 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package problem_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnozdrin/errdetail"
+	"github.com/dnozdrin/errdetail/httperr"
+
+	. "github.com/dnozdrin/errdetail/problem"
+)
+
+//nolint:gochecknoglobals // custom kinds are defined on a package initialization
+var errQuotaExceeded = errdetail.DefineKind("problem quota exceeded", errdetail.ErrResourceExhausted)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	emailDetail := errdetail.NewDetail(
+		errdetail.WithCode("invalid_email"),
+		errdetail.WithField("user.email"),
+		errdetail.WithMeta(errdetail.Meta{"pattern": "rfc5322", "attempt": 1}),
+	)
+	nameDetail := errdetail.NewDetail(
+		errdetail.WithCode("required"),
+		errdetail.WithField("user.name"),
+		errdetail.WithMeta(errdetail.Meta{"attempt": 2}),
+	)
+
+	tests := map[string]struct {
+		err  error
+		want *Problem
+	}{
+		"no_error": {
+			err:  nil,
+			want: nil,
+		},
+		"full": {
+			err: errdetail.NewInvalidArgument("bad request", emailDetail, nameDetail),
+			want: &Problem{
+				Type:   "urn:errdetail:kind:invalid-argument",
+				Title:  "invalid argument",
+				Status: http.StatusBadRequest,
+				Detail: "bad request: invalid argument",
+				Errors: []errdetail.Detail{emailDetail, nameDetail},
+				Extensions: map[string]interface{}{
+					"pattern": "rfc5322",
+					"attempt": 1,
+				},
+			},
+		},
+		"context": {
+			err: context.DeadlineExceeded,
+			want: &Problem{
+				Type:   "urn:errdetail:kind:deadline-exceeded",
+				Title:  "deadline exceeded",
+				Status: http.StatusGatewayTimeout,
+				Detail: "deadline exceeded",
+			},
+		},
+		"custom_kind": {
+			err: errdetail.Wrap(errQuotaExceeded, "daily limit"),
+			want: &Problem{
+				Type:   "urn:errdetail:kind:problem-quota-exceeded",
+				Title:  "problem quota exceeded",
+				Status: http.StatusTooManyRequests,
+				Detail: "daily limit: problem quota exceeded",
+			},
+		},
+		"unknown": {
+			err: assert.AnError,
+			want: &Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+			},
+		},
+		"internal": {
+			err: errdetail.Wrap(
+				fmt.Errorf("query orders@db-3: %w", errdetail.ErrNotFound),
+				"get order",
+				errdetail.NewDetail(errdetail.WithCode("order_not_found")),
+				errdetail.NewDetail(
+					errdetail.WithCode("shard_miss"),
+					errdetail.WithMeta(errdetail.Meta{"host": "db-3"}),
+					errdetail.WithVisibility(errdetail.VisibilityInternal),
+				),
+			),
+			want: &Problem{
+				Type:   "urn:errdetail:kind:not-found",
+				Title:  "not found",
+				Status: http.StatusNotFound,
+				Detail: "get order: not found",
+				Errors: []errdetail.Detail{errdetail.NewDetail(errdetail.WithCode("order_not_found"))},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, New(tt.err))
+		})
+	}
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := NewRenderer(
+			WithTypeBaseURI("https://example.com/problems/"),
+			WithMapper(httperr.NewMapper(
+				httperr.WithMapping(errdetail.ErrNotFound, httperr.Mapping{Status: http.StatusGone, Code: "GONE"}),
+			)),
+		)
+
+		problem := NewRenderer(WithMapper(httperr.NewMapper(
+			httperr.WithFallback(httperr.Mapping{Status: http.StatusServiceUnavailable, Code: "UNEXPECTED"}),
+		))).New(assert.AnError)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, "Service Unavailable", problem.Title, "the title of about:blank is the status phrase")
+
+		problem = renderer.New(errdetail.NewNotFound("dummy message"))
+		assert.Equal(t, "https://example.com/problems/not-found", problem.Type)
+		assert.Equal(t, http.StatusGone, problem.Status)
+
+		restored := renderer.Error(problem)
+		assert.ErrorIs(t, restored, errdetail.ErrNotFound)
+	})
+
+	t.Run("internals", func(t *testing.T) {
+		t.Parallel()
+
+		internal := errdetail.NewDetail(errdetail.WithCode("shard_miss"), errdetail.WithVisibility(errdetail.VisibilityInternal))
+		err := errdetail.Wrap(errors.New("dial db-3"), "get order", internal)
+
+		problem := NewRenderer(WithInternals()).New(err)
+		assert.Equal(t, "get order: dial db-3", problem.Detail)
+		assert.Equal(t, []errdetail.Detail{internal}, problem.Errors)
+	})
+
+	t.Run("severity", func(t *testing.T) {
+		t.Parallel()
+
+		detail := errdetail.NewDetail(
+			errdetail.WithCode("disk_full"),
+			errdetail.WithSeverity(errdetail.SeverityCritical),
+			errdetail.WithMeta(errdetail.Meta{"severity": "low", "disk": "sda"}),
+		)
+		err := errdetail.NewInternal("write order", detail)
+
+		problem := NewRenderer(WithSeverity()).New(err)
+		assert.Equal(t, map[string]interface{}{"severity": "critical", "disk": "sda"}, problem.Extensions)
+
+		problem = NewRenderer(WithSeverity()).New(errdetail.NewNotFound("order not found"))
+		assert.Equal(t, map[string]interface{}{"severity": "info"}, problem.Extensions)
+
+		problem = NewRenderer().New(err)
+		assert.Equal(t, map[string]interface{}{"severity": "low", "disk": "sda"}, problem.Extensions)
+	})
+}
+
+// TestRenderer_WriteError_redacted is not parallel, since it sets the package Redactor.
+func TestRenderer_WriteError_redacted(t *testing.T) {
+	errdetail.SetRedactor(errdetail.NewRedactor(
+		errdetail.RedactKeys(errdetail.RedactHash, "email"),
+		errdetail.RedactValues(errdetail.RedactMask, errdetail.MatchCardNumber()),
+	))
+	t.Cleanup(func() { errdetail.SetRedactor(nil) })
+
+	err := errdetail.NewFailedPrecondition("payment by 4111 1111 1111 1111 declined", errdetail.NewDetail(
+		errdetail.WithCode("card_expired"),
+		errdetail.WithReason("card 4111 1111 1111 1111 is expired"),
+		errdetail.WithMeta(errdetail.Meta{"email": "jane.doe@example.com", "card": "4111-1111-1111-1111"}),
+	))
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, httptest.NewRequest(http.MethodPost, "/payments", nil), err)
+
+	assert.Contains(t, rec.Body.String(), "sha256:")
+	assert.NotContains(t, rec.Body.String(), "jane.doe@example.com")
+	assert.NotContains(t, rec.Body.String(), "1111")
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method string
+		err    error
+		status int
+		body   string
+	}{
+		"no_error": {
+			method: http.MethodGet,
+			err:    nil,
+			status: http.StatusOK,
+		},
+		"get": {
+			method: http.MethodGet,
+			err:    errdetail.NewNotFound("user not found", errdetail.NewDetail(errdetail.WithCode("user_not_found"))),
+			status: http.StatusNotFound,
+			body: `{
+				"type": "urn:errdetail:kind:not-found",
+				"title": "not found",
+				"status": 404,
+				"detail": "user not found: not found",
+				"instance": "/users/42?verbose=1",
+				"errors": [{"code": "user_not_found"}]
+			}`,
+		},
+		"head": {
+			method: http.MethodHead,
+			err:    errdetail.NewNotFound("user not found"),
+			status: http.StatusNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			WriteError(rec, httptest.NewRequest(tt.method, "/users/42?verbose=1", nil), tt.err)
+
+			assert.Equal(t, tt.status, rec.Code)
+
+			if tt.body == "" {
+				assert.Empty(t, rec.Body.String())
+
+				return
+			}
+
+			assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		detail := errdetail.NewDetail(
+			errdetail.WithCode("user_not_found"),
+			errdetail.WithMeta(errdetail.Meta{"id": "42"}),
+		)
+		err := errdetail.NewNotFound("user not found", detail)
+
+		encoded, marshalErr := json.Marshal(New(err))
+		require.NoError(t, marshalErr)
+
+		restored, parseErr := Parse(encoded)
+		require.NoError(t, parseErr)
+
+		assert.ErrorIs(t, restored, errdetail.ErrNotFound)
+		assert.Equal(t, err.Error(), restored.Error())
+		assert.Equal(t, []errdetail.Detail{detail}, errdetail.ExtractDetails(restored))
+	})
+
+	t.Run("custom_kind", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, marshalErr := json.Marshal(New(errdetail.Wrap(errQuotaExceeded, "")))
+		require.NoError(t, marshalErr)
+
+		restored, parseErr := Parse(encoded)
+		require.NoError(t, parseErr)
+
+		assert.ErrorIs(t, restored, errQuotaExceeded)
+		assert.ErrorIs(t, restored, errdetail.ErrResourceExhausted)
+	})
+
+	tests := map[string]struct {
+		document string
+		kind     errdetail.Kind
+		message  string
+	}{
+		"foreign_type": {
+			document: `{"type": "https://example.com/probs/out-of-credit", "title": "out of credit", "status": 403}`,
+			kind:     errdetail.ErrPermissionDenied,
+			message:  "out of credit",
+		},
+		"blank_type": {
+			document: `{"status": 503, "detail": "maintenance"}`,
+			kind:     errdetail.ErrUnavailable,
+			message:  "maintenance",
+		},
+		"unknown_status": {
+			document: `{"status": 418, "title": "I'm a teapot"}`,
+			kind:     "",
+			message:  "I'm a teapot",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			restored, err := Parse([]byte(tt.document))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.kind, errdetail.KindOf(restored))
+			assert.EqualError(t, restored, tt.message)
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		t.Parallel()
+
+		restored, err := Parse([]byte(`null`))
+		assert.NoError(t, err)
+		assert.NoError(t, restored)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		restored, err := Parse([]byte(`{"status": "404"}`))
+		assert.Error(t, err)
+		assert.NoError(t, restored)
+		assert.False(t, errors.Is(err, errdetail.ErrNotFound))
+	})
+}
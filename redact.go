@@ -0,0 +1,519 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// DefaultRedactionMask is the default replacement of masked secrets.
+	DefaultRedactionMask = "[REDACTED]"
+	// hashPrefix is the prefix of hashed secrets.
+	hashPrefix = "sha256:"
+	// hashLength is the number of hex digits of a hashed secret.
+	hashLength = 16
+)
+
+// RedactMode tells how a secret is redacted.
+type RedactMode uint8
+
+const (
+	// RedactMask replaces a secret with the redaction mask, see WithRedactionMask.
+	RedactMask RedactMode = iota
+	// RedactHash replaces a secret with its truncated SHA-256 hash, e.g.
+	// sha256:5e884898da280471, so equal secrets can be correlated, while
+	// their values are not revealed. See WithRedactionKey.
+	RedactHash
+	// RedactDrop removes the Meta entry that holds a secret. Secrets found
+	// in descriptions and reasons are masked.
+	RedactDrop
+)
+
+// Matcher finds secrets in a string value. It returns the index pairs of
+// the found secrets, the same way as regexp.Regexp.FindAllStringIndex does,
+// or nil if there are none.
+type Matcher func(value string) [][]int
+
+// MatchRegexp returns a Matcher that finds the matches of the regular expression.
+func MatchRegexp(re *regexp.Regexp) Matcher {
+	return func(value string) [][]int {
+		return re.FindAllStringIndex(value, -1)
+	}
+}
+
+// MatchEmail returns a Matcher that finds email addresses.
+func MatchEmail() Matcher {
+	return MatchRegexp(regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`))
+}
+
+// MatchJWT returns a Matcher that finds JSON Web Tokens.
+func MatchJWT() Matcher {
+	return MatchRegexp(regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`))
+}
+
+// MatchCardNumber returns a Matcher that finds payment card numbers, i.e.
+// sequences of 13 to 19 digits, optionally separated by spaces or hyphens,
+// that pass the Luhn check.
+func MatchCardNumber() Matcher {
+	candidates := regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+	return func(value string) [][]int {
+		var found [][]int
+
+		for _, match := range candidates.FindAllStringIndex(value, -1) {
+			if luhnValid(value[match[0]:match[1]]) {
+				found = append(found, match)
+			}
+		}
+
+		return found
+	}
+}
+
+// luhnValid reports whether the digits of the number pass the Luhn check.
+// Characters other than digits are skipped.
+func luhnValid(number string) bool {
+	var sum int
+
+	double := false
+
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			continue
+		}
+
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// Redactor removes secrets and personal data from details before they are
+// written anywhere. A Redactor must be created by NewRedactor. It is safe
+// for concurrent use.
+type Redactor struct {
+	rules   []redactRule
+	mask    string
+	hashKey []byte
+}
+
+// redactRule redacts the Meta entries with the keys matching the patterns
+// and the secrets found by the matchers. A rule with a code applies to the
+// details with that code only.
+type redactRule struct {
+	code     string
+	keys     []string
+	matchers []Matcher
+	mode     RedactMode
+}
+
+// RedactorOption is a function type for Redactor settings' setters.
+type RedactorOption func(*Redactor)
+
+// RedactKeys is an option for Redactor constructs that redacts the values
+// of the Meta entries, including the entries of nested maps, with the keys
+// matching the patterns. The patterns are matched by path.Match against the
+// keys in lower case, e.g. "*token*" or "password". It panics if a pattern
+// is malformed.
+func RedactKeys(mode RedactMode, patterns ...string) RedactorOption {
+	keys := keyPatterns(patterns)
+
+	return func(r *Redactor) {
+		r.rules = append(r.rules, redactRule{keys: keys, mode: mode})
+	}
+}
+
+// RedactValues is an option for Redactor constructs that redacts the secrets
+// found by the matchers in the string values of Meta entries, including
+// nested maps and slices, as well as in descriptions and reasons.
+func RedactValues(mode RedactMode, matchers ...Matcher) RedactorOption {
+	return func(r *Redactor) {
+		r.rules = append(r.rules, redactRule{matchers: matchers, mode: mode})
+	}
+}
+
+// RedactCode is an option for Redactor constructs that redacts the values
+// of the Meta entries of the details with the code the same way as RedactKeys
+// does. Without patterns, all entries are redacted. The code rules take
+// precedence over the other ones.
+func RedactCode(code string, mode RedactMode, patterns ...string) RedactorOption {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+
+	keys := keyPatterns(patterns)
+
+	return func(r *Redactor) {
+		r.rules = append(r.rules, redactRule{code: code, keys: keys, mode: mode})
+	}
+}
+
+// WithRedactionMask is an option for Redactor constructs that sets the
+// replacement of masked secrets. The default one is DefaultRedactionMask.
+func WithRedactionMask(mask string) RedactorOption {
+	return func(r *Redactor) {
+		r.mask = mask
+	}
+}
+
+// WithRedactionKey is an option for Redactor constructs that makes hashed
+// secrets HMAC-SHA-256 hashes with the key, so they can not be guessed by
+// hashing known values, such as email addresses. By default, plain SHA-256
+// hashes are used.
+func WithRedactionKey(key []byte) RedactorOption {
+	return func(r *Redactor) {
+		r.hashKey = append([]byte(nil), key...)
+	}
+}
+
+// NewRedactor represents a Redactor constructor. Without options, the
+// Redactor redacts nothing.
+func NewRedactor(opts ...RedactorOption) *Redactor {
+	redactor := &Redactor{
+		mask: DefaultRedactionMask,
+	}
+
+	for i := range opts {
+		opts[i](redactor)
+	}
+
+	return redactor
+}
+
+func keyPatterns(patterns []string) []string {
+	keys := make([]string, len(patterns))
+
+	for i, pattern := range patterns {
+		keys[i] = strings.ToLower(pattern)
+
+		if _, err := path.Match(keys[i], ""); err != nil {
+			panic(fmt.Sprintf("errdetail: malformed key pattern %q", pattern))
+		}
+	}
+
+	return keys
+}
+
+// Redact returns a copy of the detail with the secrets redacted from its
+// Meta, message arguments, template arguments, description, reason and
+// payload. The payload is redacted as it is encoded to JSON and is kept
+// encoded, see Payload. Nested maps of Meta values are returned as
+// map[string]interface{}, and nested slices are returned as []interface{}.
+// Details redacted once are returned as is. A nil Redactor returns the
+// detail as is.
+func (r *Redactor) Redact(detail Detail) Detail {
+	if r == nil || detail.redacted {
+		return detail
+	}
+
+	rules := r.rulesOf(detail.code)
+
+	detail.meta = r.redactMeta(rules, detail.meta)
+	detail.messageArgs = r.redactMeta(rules, detail.messageArgs)
+	detail.templateArgs = r.redactMeta(rules, detail.templateArgs)
+	detail.description = r.redactText(rules, detail.description)
+	detail.descriptionTemplate = r.redactText(rules, detail.descriptionTemplate)
+	detail.reason = r.redactText(rules, detail.reason)
+	detail.reasonTemplate = r.redactText(rules, detail.reasonTemplate)
+	detail.payload = r.redactPayload(rules, detail.payload)
+	detail.redacted = true
+
+	return detail
+}
+
+// RedactText returns the text with the secrets found by the matchers of the
+// rules that apply to all codes masked or hashed, e.g. an error message.
+// A nil Redactor returns the text as is.
+func (r *Redactor) RedactText(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	return r.redactText(r.rulesOf(""), text)
+}
+
+// rulesOf returns the rules of the code followed by the rules of all codes.
+func (r *Redactor) rulesOf(code string) []redactRule {
+	rules := make([]redactRule, 0, len(r.rules))
+
+	for _, rule := range r.rules {
+		if rule.code != "" && rule.code == code {
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, rule := range r.rules {
+		if rule.code == "" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// redactPayload returns the payload encoded to JSON with the secrets redacted
+// the same way as in Meta values, so it can still be read by Payload. A payload
+// that can not be encoded to JSON is removed, since it can not be redacted.
+func (r *Redactor) redactPayload(rules []redactRule, payload interface{}) interface{} {
+	if payload == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil
+	}
+
+	redacted, keep := r.redactValue(rules, decoded)
+	if !keep {
+		return nil
+	}
+
+	if encoded, err = json.Marshal(redacted); err != nil {
+		return nil
+	}
+
+	return json.RawMessage(encoded)
+}
+
+func (r *Redactor) redactMeta(rules []redactRule, meta Meta) Meta {
+	if meta == nil {
+		return nil
+	}
+
+	redacted := make(Meta, len(meta))
+
+	for key, value := range meta {
+		if value, keep := r.redactEntry(rules, key, value); keep {
+			redacted[key] = value
+		}
+	}
+
+	return redacted
+}
+
+// redactEntry returns the redacted value of the entry, it reports false
+// if the entry is dropped.
+func (r *Redactor) redactEntry(rules []redactRule, key string, value interface{}) (interface{}, bool) {
+	key = strings.ToLower(key)
+
+	for _, rule := range rules {
+		for _, pattern := range rule.keys {
+			if matched, _ := path.Match(pattern, key); !matched {
+				continue
+			}
+
+			switch rule.mode {
+			case RedactDrop:
+				return nil, false
+			case RedactHash:
+				return r.hash(value), true
+			default:
+				return r.mask, true
+			}
+		}
+	}
+
+	return r.redactValue(rules, value)
+}
+
+// redactValue returns the value with the secrets redacted, it reports false
+// if the value is dropped.
+func (r *Redactor) redactValue(rules []redactRule, value interface{}) (interface{}, bool) {
+	original := reflect.ValueOf(value)
+
+	switch original.Kind() { //nolint:exhaustive // other kinds hold no strings
+	case reflect.String:
+		text, dropped := r.replaceSecrets(rules, original.String())
+		if dropped {
+			return nil, false
+		}
+
+		if text == original.String() {
+			return value, true
+		}
+
+		return text, true
+	case reflect.Map:
+		if original.IsNil() || original.Type().Key().Kind() != reflect.String {
+			return value, true
+		}
+
+		redacted := make(map[string]interface{}, original.Len())
+
+		for iter := original.MapRange(); iter.Next(); {
+			if elem, keep := r.redactEntry(rules, iter.Key().String(), iter.Value().Interface()); keep {
+				redacted[iter.Key().String()] = elem
+			}
+		}
+
+		return redacted, true
+	case reflect.Slice, reflect.Array:
+		if original.Type().Elem().Kind() == reflect.Uint8 ||
+			(original.Kind() == reflect.Slice && original.IsNil()) {
+			return value, true
+		}
+
+		redacted := make([]interface{}, 0, original.Len())
+
+		for i := 0; i < original.Len(); i++ {
+			if elem, keep := r.redactValue(rules, original.Index(i).Interface()); keep {
+				redacted = append(redacted, elem)
+			}
+		}
+
+		return redacted, true
+	default:
+		return value, true
+	}
+}
+
+// redactText returns the text with the secrets masked or hashed.
+func (r *Redactor) redactText(rules []redactRule, text string) string {
+	redacted, _ := r.replaceSecrets(rules, text)
+
+	return redacted
+}
+
+// replaceSecrets replaces the secrets found by the rules' matchers, the ones
+// to be dropped are masked. It reports whether such secrets are found.
+func (r *Redactor) replaceSecrets(rules []redactRule, text string) (string, bool) {
+	var dropped bool
+
+	for _, rule := range rules {
+		for _, match := range rule.matchers {
+			found := match(text)
+			if len(found) == 0 {
+				continue
+			}
+
+			if rule.mode == RedactDrop {
+				dropped = true
+			}
+
+			var b strings.Builder
+
+			last := 0
+
+			for _, bounds := range found {
+				b.WriteString(text[last:bounds[0]])
+
+				if rule.mode == RedactHash {
+					b.WriteString(r.hash(text[bounds[0]:bounds[1]]))
+				} else {
+					b.WriteString(r.mask)
+				}
+
+				last = bounds[1]
+			}
+
+			b.WriteString(text[last:])
+			text = b.String()
+		}
+	}
+
+	return text, dropped
+}
+
+// hash returns the truncated hash of the value, values other than strings
+// are hashed JSON encoded.
+func (r *Redactor) hash(value interface{}) string {
+	text, ok := value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(value))
+		}
+
+		text = string(encoded)
+	}
+
+	var h hash.Hash
+	if r.hashKey != nil {
+		h = hmac.New(sha256.New, r.hashKey)
+	} else {
+		h = sha256.New()
+	}
+
+	_, _ = h.Write([]byte(text))
+
+	return hashPrefix + hex.EncodeToString(h.Sum(nil))[:hashLength]
+}
+
+// redactorValue holds the package Redactor, a nil one disables redaction.
+var redactorValue atomic.Value //nolint:gochecknoglobals // package level setting
+
+type redactorHolder struct {
+	redactor *Redactor
+}
+
+// SetRedactor sets the Redactor applied whenever details are formatted,
+// marshaled to JSON, logged by slog or rendered by the httperr, problem,
+// jsonapi and grpcerr packages. A nil Redactor disables redaction, which
+// is the default.
+func SetRedactor(redactor *Redactor) {
+	redactorValue.Store(redactorHolder{redactor: redactor})
+}
+
+// Redact returns a copy of the detail redacted by the package Redactor set
+// by SetRedactor. Without a Redactor, returns the detail as is.
+func Redact(detail Detail) Detail {
+	holder, _ := redactorValue.Load().(redactorHolder)
+
+	return holder.redactor.Redact(detail)
+}
+
+// RedactText returns the text redacted by the package Redactor set by
+// SetRedactor. Without a Redactor, returns the text as is.
+func RedactText(text string) string {
+	holder, _ := redactorValue.Load().(redactorHolder)
+
+	return holder.redactor.RedactText(text)
+}
+
+// RedactDetails returns the copies of the details redacted by Redact. The
+// details themselves are not modified. Returns nil if there are no details.
+func RedactDetails(details []Detail) []Detail {
+	if len(details) == 0 {
+		return nil
+	}
+
+	redacted := make([]Detail, len(details))
+	for i := range details {
+		redacted[i] = Redact(details[i])
+	}
+
+	return redacted
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+// Severity tells how serious the problem described by a detail or an error is.
+// Severities are ordered, the greater one is the more serious one. The zero
+// Severity stands for the unspecified one.
+type Severity uint8
+
+const (
+	// SeverityDebug marks problems that are of interest for debugging only.
+	SeverityDebug Severity = iota + 1
+	// SeverityInfo marks expected problems, such as invalid client requests.
+	SeverityInfo
+	// SeverityWarning marks advisory problems, which do not prevent the
+	// operation from being completed, or problems that may need attention.
+	SeverityWarning
+	// SeverityError marks problems that prevent the operation from being completed.
+	SeverityError
+	// SeverityCritical marks problems that need immediate attention, such as data loss.
+	SeverityCritical
+)
+
+// Severities returns all severities but the unspecified one, in ascending order.
+func Severities() []Severity {
+	return []Severity{SeverityDebug, SeverityInfo, SeverityWarning, SeverityError, SeverityCritical}
+}
+
+// String returns a human-readable name of the severity, such as warning.
+// The name of the unspecified severity is empty.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return ""
+	}
+}
+
+// severityOf returns the severity with the given name. Unknown names stand
+// for the unspecified severity.
+func severityOf(name string) Severity {
+	for _, severity := range Severities() {
+		if severity.String() == name {
+			return severity
+		}
+	}
+
+	return 0
+}
+
+// WithSeverity is an option for Detail constructs that sets the detail's
+// severity. Unlike most of the other options, it does not mark the detail
+// as not empty.
+func WithSeverity(severity Severity) Option {
+	return func(d *Detail) {
+		d.severity = severity
+	}
+}
+
+// Severity is a Detail severity getter.
+func (d *Detail) Severity() Severity {
+	return d.severity
+}
+
+// SeverityOf returns the severity of the error, which is the most serious
+// of the default severity of the error's kind, see KindSeverity, and the
+// severities of the error's details, including the details of joined errors.
+// Details may raise the severity of an error, but never lower it, e.g. an
+// ErrInternal error is of SeverityError even if all its details are of
+// SeverityDebug. Returns the unspecified severity if the error is nil.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return 0
+	}
+
+	severity := KindSeverity(KindOf(err))
+
+	for _, detail := range ExtractAllDetails(err) {
+		if detail.severity > severity {
+			severity = detail.severity
+		}
+	}
+
+	return severity
+}
+
+// KindSeverity returns the default severity of errors of the kind. Client
+// errors, such as ErrInvalidArgument or ErrNotFound, are of SeverityInfo,
+// ErrUnauthenticated, ErrPermissionDenied, ErrResourceExhausted and the
+// transient server errors, such as ErrUnavailable, are of SeverityWarning,
+// ErrDataCorrupted is of SeverityCritical, and the other server errors are
+// of SeverityError. Custom kinds are of the severity of their nearest
+// predefined ancestor, errors of unknown kind are of SeverityError.
+func KindSeverity(kind Kind) Severity {
+	severities := kindSeverities()
+
+	resolved := kind.Resolve(func(kind Kind) bool {
+		_, ok := severities[kind]
+
+		return ok
+	})
+
+	if severity, ok := severities[resolved]; ok {
+		return severity
+	}
+
+	return SeverityError
+}
+
+func kindSeverities() map[Kind]Severity {
+	return map[Kind]Severity{
+		ErrInvalidArgument:    SeverityInfo,
+		ErrFailedPrecondition: SeverityInfo,
+		ErrOutOfRange:         SeverityInfo,
+		ErrUnauthenticated:    SeverityWarning,
+		ErrPermissionDenied:   SeverityWarning,
+		ErrNotFound:           SeverityInfo,
+		ErrAborted:            SeverityInfo,
+		ErrAlreadyExists:      SeverityInfo,
+		ErrRemoved:            SeverityInfo,
+		ErrResourceExhausted:  SeverityWarning,
+		ErrDataCorrupted:      SeverityCritical,
+		ErrInternal:           SeverityError,
+		ErrNotImplemented:     SeverityError,
+		ErrUnavailable:        SeverityWarning,
+		ErrDeadlineExceeded:   SeverityWarning,
+		ErrCancelled:          SeverityInfo,
+	}
+}
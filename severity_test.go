@@ -0,0 +1,116 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	names := make([]string, 0, len(Severities()))
+	for _, severity := range Severities() {
+		names = append(names, severity.String())
+	}
+
+	assert.Equal(t, []string{"debug", "info", "warning", "error", "critical"}, names)
+	assert.Equal(t, "", Severity(0).String())
+
+	detail := NewDetail(WithCode("dummy_code"))
+	assert.Equal(t, Severity(0), detail.Severity())
+
+	detail = NewDetail(WithCode("dummy_code"), WithSeverity(SeverityWarning))
+	assert.Equal(t, SeverityWarning, detail.Severity())
+
+	assert.Nil(t, ExtractDetails(New("dummy message", NewDetail(WithSeverity(SeverityWarning)))),
+		"the severity alone does not fill a detail")
+}
+
+func TestSeverityOf(t *testing.T) {
+	t.Parallel()
+
+	debug := NewDetail(WithCode("cache_miss"), WithSeverity(SeverityDebug))
+	warning := NewDetail(WithCode("quota_low"), WithSeverity(SeverityWarning))
+	critical := NewDetail(WithCode("disk_full"), WithSeverity(SeverityCritical))
+	plain := NewDetail(WithCode("order_not_found"))
+
+	tests := map[string]struct {
+		err  error
+		want Severity
+	}{
+		"nil": {
+			err:  nil,
+			want: 0,
+		},
+		"details": {
+			err:  NewNotFound("order not found", debug, warning, plain),
+			want: SeverityWarning,
+		},
+		"joined": {
+			err:  Join("sync", NewNotFound("order not found", debug), NewInternal("write order", critical)),
+			want: SeverityCritical,
+		},
+		"lower_than_kind": {
+			err:  NewInternal("cache miss", debug),
+			want: SeverityError,
+		},
+		"data_corrupted": {
+			err:  NewDataCorrupted("checksum mismatch", warning),
+			want: SeverityCritical,
+		},
+		"kind": {
+			err:  NewNotFound("order not found", plain),
+			want: SeverityInfo,
+		},
+		"context": {
+			err:  Wrap(context.DeadlineExceeded, "get order"),
+			want: SeverityWarning,
+		},
+		"custom_kind": {
+			err:  Wrap(errDailyQuotaReached, "send email"),
+			want: SeverityWarning,
+		},
+		"plain": {
+			err:  errors.New("dial tcp"),
+			want: SeverityError,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, SeverityOf(tt.err))
+		})
+	}
+}
+
+func TestKindSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[Kind]Severity{
+		ErrInvalidArgument: SeverityInfo,
+		ErrUnauthenticated: SeverityWarning,
+		ErrUnavailable:     SeverityWarning,
+		ErrDataCorrupted:   SeverityCritical,
+		ErrInternal:        SeverityError,
+		errEmailTaken:      SeverityInfo,
+		errOrphan:          SeverityError,
+		"":                 SeverityError,
+	}
+
+	for kind, want := range tests {
+		assert.Equal(t, want, KindSeverity(kind), kind)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package errdetail
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// criticalLevelOffset is the offset of the SeverityCritical level from slog.LevelError.
+const criticalLevelOffset = 4
+
+// LogValue is the `slog.LogValuer` interface implementation for Detail.
+// The detail is logged as a group of its non-empty fields, Meta entries
+// are logged as attributes of the nested meta group. The detail is redacted
+// by the package Redactor, see SetRedactor.
+func (d Detail) LogValue() slog.Value {
+	d = Redact(d)
+
+	fields := [...]slog.Attr{
+		slog.String("domain", d.domain),
+		slog.String("code", d.code),
+		slog.String("description", d.Description()),
+		slog.String("field", d.field),
+		slog.String("reason", d.Reason()),
+		slog.String("help_url", d.helpURL),
+		slog.String("severity", d.severity.String()),
+		slog.String("visibility", visibilityName(d.visibility)),
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+
+	for _, attr := range fields {
+		if attr.Value.String() != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if len(d.meta) != 0 {
+		keys := make([]string, 0, len(d.meta))
+		for key := range d.meta {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		meta := make([]slog.Attr, len(keys))
+		for i, key := range keys {
+			meta[i] = slog.Any(key, d.meta[key])
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "meta", Value: slog.GroupValue(meta...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue is the `slog.LogValuer` interface implementation for errors
+// created by New, Wrap and the predefined errors constructors.
+//
+// The error is logged as a group with the next attributes:
+//
+//	message  - the error message, as returned by Error(), redacted by RedactText;
+//	kind     - the code of the error's kind, as returned by KindOf, if any;
+//	severity - the error's severity, as returned by SeverityOf;
+//	details  - the list of the error's details, if any.
+//
+// Log allows to log errors at the level of their severity.
+func (err *wrapper) LogValue() slog.Value {
+	return errorLogValue(err)
+}
+
+// LogValue is the `slog.LogValuer` interface implementation for errors created by Join.
+// The error is logged the same way as errors created by New or Wrap.
+func (err *joinError) LogValue() slog.Value {
+	return errorLogValue(err)
+}
+
+// Level returns the slog level of the severity. The unspecified severity
+// and SeverityError stand for slog.LevelError, SeverityCritical stands for
+// the level above it.
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + criticalLevelOffset
+	default:
+		return slog.LevelError
+	}
+}
+
+// LogLevel returns the slog level of the error's severity, see SeverityOf.
+func LogLevel(err error) slog.Level {
+	return SeverityOf(err).Level()
+}
+
+// Log logs the error by the logger at the level of the error's severity. The
+// error is logged as the "error" attribute followed by the args, which are
+// handled the same way as by slog.Logger.Log.
+func Log(ctx context.Context, logger *slog.Logger, msg string, err error, args ...interface{}) {
+	logger.Log(ctx, LogLevel(err), msg, append([]interface{}{slog.Any("error", err)}, args...)...)
+}
+
+// ReplaceAttr is a function for the slog.HandlerOptions ReplaceAttr field.
+// It expands any attribute that holds an error with details into a group,
+// the same way detailed errors are logged by themselves. It allows to log
+// the details of errors wrapped by other packages, e.g. by fmt.Errorf.
+// Other attributes are returned as is.
+func ReplaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindAny {
+		return attr
+	}
+
+	err, ok := attr.Value.Any().(error)
+	if !ok || len(ExtractDetails(err)) == 0 {
+		return attr
+	}
+
+	return slog.Attr{Key: attr.Key, Value: errorLogValue(err)}
+}
+
+func errorLogValue(err error) slog.Value {
+	attrs := []slog.Attr{slog.String("message", RedactText(err.Error()))}
+
+	if kind := KindOf(err); kind != "" {
+		attrs = append(attrs, slog.String("kind", kind.Code()))
+	}
+
+	attrs = append(attrs, slog.String("severity", SeverityOf(err).String()))
+
+	if details := ExtractDetails(err); len(details) != 0 {
+		attrs = append(attrs, slog.Any("details", detailList(details)))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// detailList is a list of details logged as a JSON array by JSON handlers
+// and as a list of details' groups by text handlers.
+type detailList []Detail
+
+// MarshalJSON is the `json.Marshaler` interface implementation for detailList.
+func (l detailList) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]Detail(l))
+}
+
+// MarshalText is the `encoding.TextMarshaler` interface implementation for detailList.
+// Each detail is written as a bracketed list of key=value pairs.
+func (l detailList) MarshalText() ([]byte, error) {
+	var buf strings.Builder
+
+	buf.WriteByte('[')
+
+	for i := range l {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteByte('[')
+		writeAttrs(&buf, "", l[i].LogValue().Group())
+		buf.WriteByte(']')
+	}
+
+	buf.WriteByte(']')
+
+	return []byte(buf.String()), nil
+}
+
+// writeAttrs writes the attributes as space separated key=value pairs,
+// keys of nested groups are qualified by the groups' keys. Values that
+// contain spaces or delimiters are quoted.
+func writeAttrs(buf *strings.Builder, prefix string, attrs []slog.Attr) {
+	for i, attr := range attrs {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+
+		if attr.Value.Kind() == slog.KindGroup {
+			writeAttrs(buf, prefix+attr.Key+".", attr.Value.Group())
+
+			continue
+		}
+
+		value := attr.Value.String()
+		if value == "" || strings.ContainsAny(value, " =\"[]") {
+			value = strconv.Quote(value)
+		}
+
+		buf.WriteString(prefix + attr.Key + "=" + value)
+	}
+}
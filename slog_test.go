@@ -0,0 +1,307 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package errdetail_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestDetail_LogValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		detail Detail
+		want   []slog.Attr
+	}{
+		"empty": {
+			detail: NewDetail(),
+			want:   []slog.Attr{},
+		},
+		"full": {
+			detail: NewDetail(
+				WithDomain("user.management"),
+				WithCode("invalid_email"),
+				WithDescription("invalid email"),
+				WithField("user.email"),
+				WithReason("an email must contain @"),
+				WithMeta(Meta{"pattern": "rfc5322", "attempt": 2}),
+			),
+			want: []slog.Attr{
+				slog.String("domain", "user.management"),
+				slog.String("code", "invalid_email"),
+				slog.String("description", "invalid email"),
+				slog.String("field", "user.email"),
+				slog.String("reason", "an email must contain @"),
+				slog.Group("meta", slog.Int("attempt", 2), slog.String("pattern", "rfc5322")),
+			},
+		},
+		"templates": {
+			detail: NewDetail(
+				WithCode("invalid_email"),
+				WithReasonTemplate("an email must contain {{.char}}", Meta{"char": "@"}),
+			),
+			want: []slog.Attr{
+				slog.String("code", "invalid_email"),
+				slog.String("reason", "an email must contain @"),
+			},
+		},
+		"internal": {
+			detail: NewDetail(WithCode("shard_miss"), WithVisibility(VisibilityInternal)),
+			want: []slog.Attr{
+				slog.String("code", "shard_miss"),
+				slog.String("visibility", "internal"),
+			},
+		},
+		"severity": {
+			detail: NewDetail(WithCode("disk_full"), WithSeverity(SeverityCritical)),
+			want: []slog.Attr{
+				slog.String("code", "disk_full"),
+				slog.String("severity", "critical"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.detail.LogValue()
+
+			assert.Equal(t, slog.KindGroup, got.Kind())
+			assert.True(t, slog.GroupValue(tt.want...).Equal(got), got.String())
+		})
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	emailDetail := NewDetail(
+		WithCode("invalid_email"),
+		WithField("user.email"),
+		WithMeta(Meta{"pattern": "rfc5322"}),
+	)
+	nameDetail := NewDetail(WithCode("required"), WithField("user.name"))
+
+	tests := map[string]struct {
+		err  error
+		json string
+		text string
+	}{
+		"detailed": {
+			err: NewInvalidArgument("bad request", emailDetail, nameDetail),
+			json: `{"level":"ERROR","msg":"request failed","error":{
+				"message":"bad request: invalid argument",
+				"kind":"INVALID_ARGUMENT",
+				"severity":"info",
+				"details":[
+					{"code":"invalid_email","field":"user.email","meta":{"pattern":"rfc5322"}},
+					{"code":"required","field":"user.name"}
+				]
+			}}`,
+			text: `level=ERROR msg="request failed" error.message="bad request: invalid argument" ` +
+				`error.kind=INVALID_ARGUMENT error.severity=info ` +
+				`error.details="[[code=invalid_email field=user.email meta.pattern=rfc5322] ` +
+				`[code=required field=user.name]]"` + "\n",
+		},
+		"joined": {
+			err: Join("validation failed", New("no email", emailDetail)),
+			json: `{"level":"ERROR","msg":"request failed","error":{
+				"message":"validation failed: no email",
+				"severity":"error",
+				"details":[{"code":"invalid_email","field":"user.email","meta":{"pattern":"rfc5322"}}]
+			}}`,
+			text: `level=ERROR msg="request failed" error.message="validation failed: no email" ` +
+				`error.severity=error error.details="[[code=invalid_email field=user.email meta.pattern=rfc5322]]"` + "\n",
+		},
+		"no_details": {
+			err: Wrap(ErrNotFound, "user not found"),
+			json: `{"level":"ERROR","msg":"request failed","error":{
+				"message":"user not found: not found",
+				"kind":"NOT_FOUND",
+				"severity":"info"
+			}}`,
+			text: `level=ERROR msg="request failed" error.message="user not found: not found" error.kind=NOT_FOUND ` +
+				`error.severity=info` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.JSONEq(t, tt.json, logJSON(nil, slog.Any("error", tt.err)))
+			assert.Equal(t, tt.text, logText(slog.Any("error", tt.err)))
+		})
+	}
+}
+
+func TestReplaceAttr(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithCode("user_blocked"), WithMeta(Meta{"user": "42"}))
+
+	tests := map[string]struct {
+		attr slog.Attr
+		want string
+	}{
+		"wrapped": {
+			attr: slog.Any("error", fmt.Errorf("handle request: %w", NewPermissionDenied("access denied", detail))),
+			want: `{"level":"ERROR","msg":"request failed","error":{
+				"message":"handle request: access denied: permission denied",
+				"kind":"PERMISSION_DENIED",
+				"severity":"warning",
+				"details":[{"code":"user_blocked","meta":{"user":"42"}}]
+			}}`,
+		},
+		"no_details": {
+			attr: slog.Any("error", fmt.Errorf("handle request: %w", ErrNotFound)),
+			want: `{"level":"ERROR","msg":"request failed","error":"handle request: not found"}`,
+		},
+		"not_error": {
+			attr: slog.String("error", "access denied"),
+			want: `{"level":"ERROR","msg":"request failed","error":"access denied"}`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.JSONEq(t, tt.want, logJSON(ReplaceAttr, tt.attr))
+		})
+	}
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err   error
+		level slog.Level
+	}{
+		"kind": {
+			err:   NewNotFound("user not found"),
+			level: slog.LevelInfo,
+		},
+		"detail": {
+			err:   NewNotFound("user not found", NewDetail(WithCode("user_missing"), WithSeverity(SeverityWarning))),
+			level: slog.LevelWarn,
+		},
+		"critical": {
+			err:   NewInternal("disk failed", NewDetail(WithCode("disk_full"), WithSeverity(SeverityCritical))),
+			level: slog.LevelError + 4,
+		},
+		"plain": {
+			err:   fmt.Errorf("dial tcp"),
+			level: slog.LevelError,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.level, LogLevel(tt.err))
+
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			Log(context.Background(), logger, "request failed", tt.err, "user", "42")
+
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+			assert.Equal(t, tt.level.String(), record["level"])
+			assert.Equal(t, "42", record["user"])
+			assert.NotNil(t, record["error"])
+		})
+	}
+
+	assert.Equal(t, slog.LevelWarn, SeverityWarning.Level())
+	assert.Equal(t, slog.LevelError, Severity(0).Level())
+}
+
+// logJSON logs the attribute by a JSON handler without time and returns the output.
+func logJSON(replace func([]string, slog.Attr) slog.Attr, attr slog.Attr) string {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+
+			if replace != nil {
+				return replace(groups, a)
+			}
+
+			return a
+		},
+	}))
+	logger.Error("request failed", attr)
+
+	return buf.String()
+}
+
+// logText logs the attribute by a text handler without time and returns the output.
+func logText(attr slog.Attr) string {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+	logger.Error("request failed", attr)
+
+	return buf.String()
+}
+
+// TestSetRedactor_slog checks that no secret reaches the slog output.
+// It is not parallel, since it sets the package Redactor.
+func TestSetRedactor_slog(t *testing.T) {
+	SetRedactor(newTestRedactor())
+	t.Cleanup(func() { SetRedactor(nil) })
+
+	detail := newSecretDetail()
+	err := Wrap(NewInvalidArgument("payment of "+secretEmail+" failed", detail), "checkout")
+
+	var logs bytes.Buffer
+
+	slog.New(slog.NewJSONHandler(&logs, nil)).Error("checkout", "error", err, "detail", detail)
+	slog.New(slog.NewTextHandler(&logs, nil)).Error("checkout", "error", err, "detail", detail)
+
+	assert.Contains(t, logs.String(), "[REDACTED]")
+
+	for _, secret := range secrets() {
+		assert.NotContains(t, logs.String(), secret)
+	}
+}
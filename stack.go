@@ -0,0 +1,94 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+// stackTraceEnabled is accessed atomically, 1 stands for enabled capturing.
+var stackTraceEnabled int32 //nolint:gochecknoglobals // package level toggle
+
+// EnableStackTrace turns on stack trace capturing for errors created
+// by New, Wrap and the predefined errors constructors. The capturing
+// is disabled by default.
+func EnableStackTrace() {
+	atomic.StoreInt32(&stackTraceEnabled, 1)
+}
+
+// DisableStackTrace turns off stack trace capturing.
+func DisableStackTrace() {
+	atomic.StoreInt32(&stackTraceEnabled, 0)
+}
+
+// StackTrace is a stack of program counters recorded at an error creation.
+// It can be resolved into frames by runtime.CallersFrames.
+type StackTrace []uintptr
+
+// Format is the `fmt.Formatter` interface implementation for StackTrace.
+// The %+v verb prints a function name and a source file line of each frame,
+// the %s and %v verbs print function names only.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	frames := runtime.CallersFrames(st)
+
+	var separator string
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case verb == 'v' && s.Flag('+'):
+			fmt.Fprintf(s, "%s%s\n\t%s:%d", separator, frame.Function, frame.File, frame.Line)
+		case verb == 'v', verb == 's':
+			_, _ = io.WriteString(s, separator+frame.Function)
+		default:
+			fmt.Fprintf(s, "%%!%c(errdetail.StackTrace)", verb)
+
+			return
+		}
+
+		if !more {
+			return
+		}
+
+		separator = "\n"
+	}
+}
+
+type stackTracer interface {
+	StackTrace() StackTrace
+}
+
+// ExtractStackTrace extracts a stack trace from an error, if any. Otherwise, returns nil.
+func ExtractStackTrace(err error) StackTrace {
+	var st stackTracer
+	if errors.As(err, &st) {
+		return st.StackTrace()
+	}
+
+	return nil
+}
+
+// callers records the stack of the caller of the function that calls callers.
+// It returns nil if the capturing is disabled or the stack has been already
+// recorded in the err's chain.
+func callers(err error) StackTrace {
+	if atomic.LoadInt32(&stackTraceEnabled) == 0 || ExtractStackTrace(err) != nil {
+		return nil
+	}
+
+	const skip = 4 // runtime.Callers, callers, wrap and New or Wrap
+
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+
+	return pcs[:n]
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+const testFunctionPrefix = "github.com/dnozdrin/errdetail_test.TestStackTrace"
+
+func TestStackTraceDisabled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ExtractStackTrace(New("dummy message")))
+	assert.Nil(t, ExtractStackTrace(Wrap(assert.AnError, "dummy message")))
+	assert.Nil(t, ExtractStackTrace(NewInternal("dummy message")))
+	assert.Nil(t, ExtractStackTrace(assert.AnError))
+	assert.Nil(t, ExtractStackTrace(nil))
+}
+
+//nolint:paralleltest // the test changes the package level toggle
+func TestStackTrace(t *testing.T) {
+	EnableStackTrace()
+	defer DisableStackTrace()
+
+	constructors := map[string]func() error{
+		"new": func() error {
+			return New("dummy message")
+		},
+		"wrap": func() error {
+			return Wrap(assert.AnError, "dummy message")
+		},
+		"predefined": func() error {
+			return NewInternal("dummy message")
+		},
+	}
+
+	for name, constructor := range constructors {
+		constructor := constructor
+
+		t.Run(name, func(t *testing.T) {
+			err := constructor()
+
+			st := ExtractStackTrace(err)
+			require.NotEmpty(t, st)
+
+			frame, _ := runtime.CallersFrames(st).Next()
+			assert.True(t, strings.HasPrefix(frame.Function, testFunctionPrefix), frame.Function)
+
+			assert.True(t, strings.HasPrefix(fmt.Sprintf("%v", st), testFunctionPrefix))
+			assert.Contains(t, fmt.Sprintf("%+v", st), "stack_test.go:")
+			assert.Contains(t, fmt.Sprintf("%+v", err), "\n    stack:\n      "+testFunctionPrefix)
+			assert.Equal(t, "%!d(errdetail.StackTrace)", fmt.Sprintf("%d", st))
+		})
+	}
+
+	t.Run("recorded_once", func(t *testing.T) {
+		inner := NewNotFound("dummy message")
+		outer := Wrap(fmt.Errorf("%w", inner), "dummy message")
+
+		assert.Equal(t, ExtractStackTrace(inner), ExtractStackTrace(outer))
+		assert.Equal(t, 1, strings.Count(fmt.Sprintf("%+v", outer), "stack:"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		DisableStackTrace()
+		defer EnableStackTrace()
+
+		assert.Nil(t, ExtractStackTrace(New("dummy message")))
+	})
+}
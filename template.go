@@ -0,0 +1,139 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+)
+
+// maxTemplates limits the number of cached templates, the templates beyond
+// the limit are parsed on every rendering.
+const maxTemplates = 1024
+
+//nolint:gochecknoglobals // the cache is safe for concurrent use
+var (
+	// templates caches the parsed templates by their text, a nil template marks
+	// a malformed one. Templates are parsed once, since details are rendered by
+	// every formatting, encoding and logging.
+	templates sync.Map
+	// templatesCount is the number of the cached templates.
+	templatesCount int64
+)
+
+// WithDescriptionTemplate is an option for Detail constructs that sets
+// a text/template of the description along with the template arguments,
+// and marks the detail as not empty. The template is rendered lazily by
+// Description, so the arguments stay available to renderers and translators.
+// The arguments are shared by the description and the reason templates,
+// the ones set later take precedence. The option overrides WithDescription.
+func WithDescriptionTemplate(text string, args Meta) Option {
+	return func(d *Detail) {
+		d.description = ""
+		d.descriptionTemplate = text
+		d.addTemplateArgs(args)
+
+		if d.descriptionTemplate != "" {
+			d.filled = true
+		}
+	}
+}
+
+// WithReasonTemplate is an option for Detail constructs that sets
+// a text/template of the reason along with the template arguments, e.g.
+//
+//	WithReasonTemplate("invalid character detected: {{.char}}", Meta{"char": "#"})
+//
+// and marks the detail as not empty. The template is rendered lazily by
+// Reason, the arguments are handled the same way as by WithDescriptionTemplate.
+// The option overrides WithReason.
+func WithReasonTemplate(text string, args Meta) Option {
+	return func(d *Detail) {
+		d.reason = ""
+		d.reasonTemplate = text
+		d.addTemplateArgs(args)
+
+		if d.reasonTemplate != "" {
+			d.filled = true
+		}
+	}
+}
+
+// DescriptionTemplate is a Detail description template getter.
+func (d *Detail) DescriptionTemplate() string {
+	return d.descriptionTemplate
+}
+
+// ReasonTemplate is a Detail reason template getter.
+func (d *Detail) ReasonTemplate() string {
+	return d.reasonTemplate
+}
+
+// TemplateArgs is a Detail template arguments getter.
+// It returns a copy, so the detail can not be modified by the caller.
+func (d *Detail) TemplateArgs() Meta {
+	return d.templateArgs.clone()
+}
+
+// addTemplateArgs copies the arguments into the detail's template arguments.
+func (d *Detail) addTemplateArgs(args Meta) {
+	if len(args) == 0 {
+		return
+	}
+
+	merged := d.templateArgs.clone()
+	if merged == nil {
+		merged = make(Meta, len(args))
+	}
+
+	for key, value := range args.clone() {
+		merged[key] = value
+	}
+
+	d.templateArgs = merged
+}
+
+// renderTemplate executes the template with the arguments. If the template
+// is malformed or fails, e.g. refers to a missing argument, the template
+// text is returned as is.
+func renderTemplate(text string, args Meta) string {
+	tmpl := parseTemplate(text)
+	if tmpl == nil {
+		return text
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, args); err != nil {
+		return text
+	}
+
+	return b.String()
+}
+
+// parseTemplate returns the parsed template with the text, or nil if the
+// template is malformed.
+func parseTemplate(text string) *template.Template {
+	if cached, ok := templates.Load(text); ok {
+		return cached.(*template.Template) //nolint:forcetypeassert // only templates are stored
+	}
+
+	tmpl, err := template.New("detail").Option("missingkey=error").Parse(text)
+	if err != nil {
+		tmpl = nil
+	}
+
+	if atomic.LoadInt64(&templatesCount) >= maxTemplates {
+		return tmpl
+	}
+
+	cached, loaded := templates.LoadOrStore(text, tmpl)
+	if !loaded {
+		atomic.AddInt64(&templatesCount, 1)
+	}
+
+	return cached.(*template.Template) //nolint:forcetypeassert // only templates are stored
+}
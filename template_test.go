@@ -0,0 +1,174 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestDetail_templates(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts        []Option
+		description string
+		reason      string
+		args        Meta
+	}{
+		"reason_template": {
+			opts: []Option{
+				WithReasonTemplate("invalid character detected: {{.char}}", Meta{"char": "#"}),
+			},
+			reason: "invalid character detected: #",
+			args:   Meta{"char": "#"},
+		},
+		"shared_args": {
+			opts: []Option{
+				WithDescriptionTemplate("{{.field}} validation failed", Meta{"field": "email", "char": "@"}),
+				WithReasonTemplate("invalid character detected: {{printf \"%q\" .char}}", Meta{"char": "#"}),
+			},
+			description: "email validation failed",
+			reason:      `invalid character detected: "#"`,
+			args:        Meta{"field": "email", "char": "#"},
+		},
+		"template_overrides_text": {
+			opts: []Option{
+				WithReason("plain"),
+				WithReasonTemplate("{{.n}} items", Meta{"n": 3}),
+			},
+			reason: "3 items",
+			args:   Meta{"n": 3},
+		},
+		"text_overrides_template": {
+			opts: []Option{
+				WithDescriptionTemplate("{{.n}} items", Meta{"n": 3}),
+				WithDescription("plain"),
+			},
+			description: "plain",
+			args:        Meta{"n": 3},
+		},
+		"missing_arg": {
+			opts: []Option{
+				WithReasonTemplate("invalid character detected: {{.char}}", nil),
+			},
+			reason: "invalid character detected: {{.char}}",
+		},
+		"malformed_template": {
+			opts: []Option{
+				WithReasonTemplate("invalid character detected: {{.char", Meta{"char": "#"}),
+			},
+			reason: "invalid character detected: {{.char",
+			args:   Meta{"char": "#"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			detail := NewDetail(tt.opts...)
+
+			assert.Equal(t, tt.description, detail.Description())
+			assert.Equal(t, tt.reason, detail.Reason())
+			assert.Equal(t, tt.args, detail.TemplateArgs())
+		})
+	}
+}
+
+func TestDetail_templates_lazy(t *testing.T) {
+	t.Parallel()
+
+	args := Meta{"chars": []string{"#"}}
+	detail := NewDetail(WithReasonTemplate("invalid characters: {{.chars}}", args))
+
+	args["chars"] = []string{"%"}
+	detail.TemplateArgs()["chars"].([]string)[0] = "$"
+
+	assert.Equal(t, "invalid characters: {{.chars}}", detail.ReasonTemplate())
+	assert.Equal(t, "invalid characters: [#]", detail.Reason())
+	assert.Empty(t, detail.DescriptionTemplate())
+	assert.True(t, len(ExtractDetails(New("dummy", detail))) == 1)
+}
+
+func TestDetail_templates_concurrent(t *testing.T) {
+	t.Parallel()
+
+	valid := NewDetail(WithReasonTemplate("order {{.id}} is locked", Meta{"id": 42}))
+	malformed := NewDetail(WithReasonTemplate("order {{.id is locked", Meta{"id": 42}))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, "order 42 is locked", valid.Reason())
+				assert.Equal(t, "order {{.id is locked", malformed.Reason())
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestDetail_templates_many(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 2000; i++ {
+		detail := NewDetail(WithReasonTemplate(fmt.Sprintf("order {{.id}} is locked by %d", i), Meta{"id": 42}))
+		assert.Equal(t, fmt.Sprintf("order 42 is locked by %d", i), detail.Reason())
+	}
+}
+
+func TestDetail_templates_outputs(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(
+		WithCode("invalid_email"),
+		WithDescriptionTemplate("{{.field}} validation failed", nil),
+		WithReasonTemplate("invalid character detected: {{.char}}", Meta{"char": "#", "field": "email"}),
+	)
+
+	encoded, err := json.Marshal(detail)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"code": "invalid_email",
+		"description": "email validation failed",
+		"reason": "invalid character detected: #",
+		"description_template": "{{.field}} validation failed",
+		"reason_template": "invalid character detected: {{.char}}",
+		"template_args": {"char": "#", "field": "email"}
+	}`, string(encoded))
+
+	var decoded Detail
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, detail.Description(), decoded.Description())
+	assert.Equal(t, detail.Reason(), decoded.Reason())
+	assert.Equal(t, detail.TemplateArgs(), decoded.TemplateArgs())
+	assert.Empty(t, decoded.DescriptionTemplate(), "templates are not decoded")
+	assert.Empty(t, decoded.ReasonTemplate(), "templates are not decoded")
+
+	var received Detail
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"reason": "x", "reason_template": "{{.a}}{{.a}}", "template_args": {"a": "y"}}`), &received))
+	assert.Equal(t, "x", received.Reason(), "received templates are not executed")
+
+	formatted := fmt.Sprintf("%+v", New("dummy", detail))
+	assert.Contains(t, formatted, "reason: invalid character detected: #")
+	assert.Contains(t, formatted, "description: email validation failed")
+}
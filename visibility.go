@@ -0,0 +1,168 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail
+
+import (
+	"errors"
+	"strings"
+)
+
+// Visibility tells whether a detail or a layer's message may be shown to clients.
+type Visibility uint8
+
+const (
+	// VisibilityPublic marks details and messages that are safe to be shown
+	// to clients. It is the default visibility.
+	VisibilityPublic Visibility = iota
+	// VisibilityInternal marks details and messages that must not leave
+	// the service, such as queries, host names or identifiers of internal
+	// resources. They are hidden by PublicView.
+	VisibilityInternal
+)
+
+const internalVisibility = "internal"
+
+// String returns a human-readable name of the visibility.
+func (v Visibility) String() string {
+	if v == VisibilityInternal {
+		return internalVisibility
+	}
+
+	return "public"
+}
+
+// visibilityName returns the name of the visibility if it is not the default one.
+func visibilityName(visibility Visibility) string {
+	if visibility == VisibilityPublic {
+		return ""
+	}
+
+	return visibility.String()
+}
+
+// visibilityOf returns the visibility with the given name. Unknown names
+// stand for the public visibility.
+func visibilityOf(name string) Visibility {
+	if name == internalVisibility {
+		return VisibilityInternal
+	}
+
+	return VisibilityPublic
+}
+
+// WithVisibility is an option for Detail constructs that sets the detail's
+// visibility. Unlike the other options, it does not mark the detail as not
+// empty. Details are public by default.
+func WithVisibility(visibility Visibility) Option {
+	return func(d *Detail) {
+		d.visibility = visibility
+	}
+}
+
+// Visibility is a Detail visibility getter.
+func (d *Detail) Visibility() Visibility {
+	return d.visibility
+}
+
+// Mark returns the error with the message of its outermost layer marked by
+// the visibility, e.g.
+//
+//	errdetail.Mark(errdetail.Wrap(err, "query orders of tenant 42"), errdetail.VisibilityInternal)
+//
+// The layer's details are not affected. Messages of the layers created by New,
+// Wrap, Restore, Join and the predefined errors constructors are public by
+// default. Other errors are returned as is, since their messages are never
+// shown by PublicView. The original error is not modified.
+func Mark(err error, visibility Visibility) error {
+	switch layer := err.(type) { //nolint:errorlint // a particular layer is marked
+	case *wrapper:
+		marked := *layer
+		marked.visibility = visibility
+
+		return &marked
+	case *joinError:
+		marked := *layer
+		marked.visibility = visibility
+
+		return &marked
+	default:
+		return err
+	}
+}
+
+// PublicView returns the error as it is safe to be shown to clients. The
+// returned error keeps the kind of the original one, while its message is
+// built of the public messages of the layers created by this package, and its
+// details are the public ones. The messages of the other errors in the chain,
+// such as database or network errors wrapped by Wrap, are never included. If
+// there are no public messages, the message is the kind's one, or empty if
+// the error has no kind. The stack trace is not kept. Returns nil if the
+// error is nil.
+//
+// The renderers of the httperr, problem, jsonapi and grpcerr packages render
+// the public view of errors by default.
+func PublicView(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var public []Detail
+
+	for _, detail := range ExtractDetails(err) {
+		if detail.visibility == VisibilityPublic {
+			public = append(public, detail)
+		}
+	}
+
+	kind := KindOf(err)
+
+	return Restore(publicMessage(err, kind), kind, public...)
+}
+
+// publicMessage returns the public messages of the error's layers joined
+// the same way as Wrap and Join do.
+func publicMessage(err error, kind Kind) string {
+	var msgs []string
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		switch layer := current.(type) { //nolint:errorlint // a particular layer is inspected
+		case *wrapper:
+			if layer.visibility == VisibilityPublic && layer.text != "" {
+				msgs = append(msgs, layer.text)
+			}
+		case *joinError:
+			if layer.visibility == VisibilityPublic && layer.text != "" {
+				msgs = append(msgs, layer.text)
+			}
+
+			if joined := joinedMessage(layer.errs); joined != "" {
+				msgs = append(msgs, joined)
+			}
+
+			return strings.Join(msgs, ": ")
+		case Kind:
+			return strings.Join(append(msgs, layer.Error()), ": ")
+		}
+	}
+
+	if kind != "" {
+		msgs = append(msgs, kind.Error())
+	}
+
+	return strings.Join(msgs, ": ")
+}
+
+// joinedMessage returns the public messages of the joined errors.
+func joinedMessage(errs []error) string {
+	msgs := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		if msg := publicMessage(err, KindOf(err)); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return strings.Join(msgs, joinSeparator)
+}
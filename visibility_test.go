@@ -0,0 +1,153 @@
+// Copyright 2022 Dmytro Nozdrin. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package errdetail_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/dnozdrin/errdetail"
+)
+
+func TestVisibility(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "public", VisibilityPublic.String())
+	assert.Equal(t, "internal", VisibilityInternal.String())
+
+	detail := NewDetail(WithCode("dummy_code"))
+	assert.Equal(t, VisibilityPublic, detail.Visibility())
+
+	detail = NewDetail(WithCode("dummy_code"), WithVisibility(VisibilityInternal))
+	assert.Equal(t, VisibilityInternal, detail.Visibility())
+
+	assert.Nil(t, ExtractDetails(New("dummy message", NewDetail(WithVisibility(VisibilityInternal)))),
+		"the visibility alone does not fill a detail")
+}
+
+func TestPublicView(t *testing.T) {
+	t.Parallel()
+
+	public := NewDetail(WithCode("order_not_found"))
+	internal := NewDetail(WithCode("shard_miss"), WithVisibility(VisibilityInternal))
+	cause := errors.New("dial tcp 10.0.0.3:5432: connection refused")
+
+	tests := map[string]struct {
+		err     error
+		msg     string
+		kind    Kind
+		details []Detail
+	}{
+		"public": {
+			err:     NewNotFound("order not found", public),
+			msg:     "order not found: not found",
+			kind:    ErrNotFound,
+			details: []Detail{public},
+		},
+		"internal_details": {
+			err:     NewNotFound("order not found", public, internal),
+			msg:     "order not found: not found",
+			kind:    ErrNotFound,
+			details: []Detail{public},
+		},
+		"foreign_cause": {
+			err:  Wrap(cause, "get order"),
+			msg:  "get order",
+			kind: "",
+		},
+		"foreign_layer": {
+			err:     Wrap(fmt.Errorf("query orders@db-3: %w", NewNotFound("order not found", public)), "get order"),
+			msg:     "get order: order not found: not found",
+			kind:    ErrNotFound,
+			details: []Detail{public},
+		},
+		"internal_layer": {
+			err:     Wrap(Mark(Wrap(ErrNotFound, "no rows in orders@db-3", internal), VisibilityInternal), "get order"),
+			msg:     "get order: not found",
+			kind:    ErrNotFound,
+			details: nil,
+		},
+		"internal_only": {
+			err:  Mark(NewInternal("dial db-3"), VisibilityInternal),
+			msg:  "internal",
+			kind: ErrInternal,
+		},
+		"context": {
+			err:  Wrap(context.DeadlineExceeded, "get order"),
+			msg:  "get order: deadline exceeded",
+			kind: ErrDeadlineExceeded,
+		},
+		"join": {
+			err: Join("validate order",
+				NewInvalidArgument("invalid amount", public),
+				Mark(NewNotFound("no customer 42 in crm", internal), VisibilityInternal),
+				cause,
+			),
+			msg:     "validate order: invalid amount: invalid argument; not found",
+			kind:    ErrInvalidArgument,
+			details: []Detail{public},
+		},
+		"internal_join": {
+			err:  Mark(Join("sync shard 3", NewNotFound("order not found")), VisibilityInternal),
+			msg:  "order not found: not found",
+			kind: ErrNotFound,
+		},
+		"plain": {
+			err:  cause,
+			msg:  "",
+			kind: "",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			view := PublicView(tt.err)
+			require.Error(t, view)
+
+			assert.Equal(t, tt.msg, view.Error())
+			assert.Equal(t, tt.kind, KindOf(view))
+			assert.Equal(t, tt.details, ExtractDetails(view))
+			assert.Nil(t, ExtractStackTrace(view))
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, PublicView(nil))
+	})
+}
+
+func TestMark(t *testing.T) {
+	t.Parallel()
+
+	detail := NewDetail(WithCode("order_not_found"))
+	original := Wrap(ErrNotFound, "no rows in orders@db-3", detail)
+
+	marked := Mark(original, VisibilityInternal)
+	assert.Equal(t, original.Error(), marked.Error())
+	assert.Equal(t, ExtractDetails(original), ExtractDetails(marked))
+	assert.Equal(t, ExtractStackTrace(original), ExtractStackTrace(marked))
+	assert.ErrorIs(t, marked, ErrNotFound)
+
+	assert.Equal(t, VisibilityInternal, Chain(marked)[0].Visibility)
+	assert.Equal(t, VisibilityPublic, Chain(original)[0].Visibility, "the original error is not modified")
+	assert.Equal(t, VisibilityPublic, Chain(Mark(marked, VisibilityPublic))[0].Visibility)
+
+	joined := Mark(Join("sync", original), VisibilityInternal)
+	assert.Equal(t, VisibilityInternal, Chain(joined)[0].Visibility)
+
+	assert.Equal(t, context.Canceled, Mark(context.Canceled, VisibilityInternal))
+	assert.NoError(t, Mark(nil, VisibilityInternal))
+}